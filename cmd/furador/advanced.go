@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"furador-de-coco/config"
+	"furador-de-coco/finding"
+	"furador-de-coco/logger"
+	"furador-de-coco/report"
+	"furador-de-coco/scanner"
+)
+
+// runAdvancedCommand executa todas as verificações selecionadas do registro
+// e os templates no worker pool, com os mesmos relatórios do scan
+func runAdvancedCommand(ctx context.Context, args []string) {
+	cfg := parseConfig("advanced", config.FlagsTarget|config.FlagsLimits|config.FlagsCrawl|config.FlagsOutput|config.FlagsChecks|config.FlagsResume, args, nil)
+
+	logger.Info("Iniciando scan avançado em: %s", cfg.URL)
+	logger.Info("Workers: %d | Timeout: %v | Limite: %s", cfg.Workers, cfg.Timeout, limitsSummary(cfg))
+	safety := setupScanner(cfg)
+	if safety < scanner.SafetyIntrusive {
+		logger.Warn("Traversal, LFI, XXE, Command Injection e SSRF exigem -safety intrusive e serão ignorados")
+	}
+
+	checks, err := selectChecks(cfg)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
+
+	templates, err := scanner.LoadTemplates(cfg.TemplatesDir)
+	if err != nil {
+		logger.Fatal("Erro ao carregar templates: %v", err)
+	}
+
+	httpClient := setupHTTPClient(cfg)
+
+	ctx, st := openState(ctx, cfg, "advanced")
+
+	scanReport := &report.ScanReport{
+		StartTime:   st.Started(),
+		TargetURL:   cfg.URL,
+		SafetyLevel: safety.String(),
+	}
+
+	// Busca formulários
+	logger.Info("Buscando formulários...")
+	targets, err := loadTargets(ctx, cfg, httpClient, st)
+	if err != nil {
+		logger.Fatal("Erro ao buscar formulários: %v", err)
+	}
+	forms := targets.Forms
+	scanReport.Endpoints = targets.Endpoints
+	logger.Success("Encontrados %d formulário(s)", len(forms))
+
+	// Verificações do registro (XSS, SQLi, avançadas, headers, cookies, CSRF)
+	logger.Info("Executando verificações: %s", checkList(checks))
+	results := scanner.RunChecksParallel(ctx, checks, cfg.URL, forms, httpClient, cfg.Workers)
+
+	// Templates declarativos
+	logger.Info("Executando %d template(s)...", len(templates))
+	results.Merge(scanner.RunTemplates(ctx, templates, cfg.URL, httpClient, cfg.Workers))
+
+	scanReport.FormsScanned = len(forms)
+	finishReport(ctx, scanReport, results)
+	findings, inconclusive := results.Findings, results.Inconclusive
+	scanner.PrintFindings(findings)
+
+	// Resumo final
+	fmt.Println("\n" + strings.Repeat("=", 80))
+	if scanReport.Incomplete {
+		fmt.Printf("\nSCAN INTERROMPIDO - RESULTADOS PARCIAIS")
+	} else {
+		fmt.Printf("\nSCAN COMPLETO!")
+	}
+	fmt.Printf("\nFormas testadas: %d", len(forms))
+	fmt.Printf("\nVulnerabilidades críticas encontradas: %d", finding.Count(findings, finding.SeverityCritical))
+	fmt.Printf("\nTotal de vulnerabilidades: %d\n", len(findings))
+	if len(inconclusive) > 0 {
+		fmt.Printf("Verificações inconclusivas: %d\n", len(inconclusive))
+	}
+
+	if len(findings) > 0 {
+		fmt.Println("\n[!!!] SISTEMA VULNERÁVEL - CORREÇÕES NECESSÁRIAS!")
+	} else {
+		fmt.Println("\n[OK] Nenhuma vulnerabilidade crítica detectada")
+	}
+
+	saveReports(cfg, scanReport)
+	closeState(st, "advanced", scanReport.Incomplete, len(inconclusive))
+
+	report.PrintRiskScore(findings)
+
+	if !scanReport.Incomplete {
+		logger.Success("Scan avançado concluído!")
+	}
+}
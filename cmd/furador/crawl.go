@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"furador-de-coco/config"
+	"furador-de-coco/logger"
+)
+
+// runCrawlCommand percorre o site e lista o que o scan testaria, sem enviar
+// payloads
+func runCrawlCommand(ctx context.Context, args []string) {
+	cfg := parseConfig("crawl", config.FlagsTarget|config.FlagsLimits|config.FlagsCrawl, args, nil)
+	cfg.Crawl = true
+
+	httpClient := setupHTTPClient(cfg)
+
+	targets, err := getForms(ctx, cfg, httpClient)
+	if err != nil {
+		logger.Fatal("Erro ao percorrer o site: %v", err)
+	}
+
+	fmt.Printf("\n=== PÁGINAS (%d) ===\n", len(targets.Pages))
+	for _, page := range targets.Pages {
+		fmt.Println(page)
+	}
+
+	fmt.Printf("\n=== FORMULÁRIOS (%d) ===\n", len(targets.Forms))
+	for _, form := range targets.Forms {
+		method := strings.ToUpper(form.Method)
+		if method == "" {
+			method = "GET"
+		}
+		var fields []string
+		for _, input := range form.Inputs {
+			if input.Name != "" {
+				fields = append(fields, input.Name)
+			}
+		}
+		fmt.Printf("%s %s (em %s) campos: %s\n", method, form.Action, formBaseURL(form, cfg.URL), strings.Join(fields, ", "))
+	}
+
+	if len(targets.Endpoints) > 0 {
+		fmt.Printf("\n=== ENDPOINTS DESCOBERTOS (%d) ===\n", len(targets.Endpoints))
+		for _, ep := range targets.Endpoints {
+			marker := " "
+			if ep.Interesting {
+				marker = "!"
+			}
+			fmt.Printf("[%s] %s (%s)\n", marker, ep.URL, ep.Source)
+		}
+	}
+}
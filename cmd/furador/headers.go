@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"furador-de-coco/config"
+	"furador-de-coco/logger"
+	"furador-de-coco/report"
+	"furador-de-coco/scanner"
+)
+
+// runHeadersCommand analisa os headers de segurança e os cookies da URL
+// alvo, sem enviar payloads
+func runHeadersCommand(ctx context.Context, args []string) {
+	cfg := parseConfig("headers", config.FlagsTarget|config.FlagsLimits|config.FlagsOutput, args, nil)
+
+	checks, err := scanner.SelectChecks([]string{"headers", "cookies"}, nil)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
+
+	httpClient := setupHTTPClient(cfg)
+
+	scanReport := &report.ScanReport{
+		StartTime: time.Now(),
+		TargetURL: cfg.URL,
+	}
+
+	logger.Info("Analisando headers e cookies de %s...", cfg.URL)
+	results := scanner.RunChecks(ctx, checks, cfg.URL, nil, httpClient)
+	finishReport(ctx, scanReport, results)
+	scanner.PrintFindings(results.Findings)
+
+	saveReports(cfg, scanReport)
+
+	report.PrintRiskScore(results.Findings)
+}
@@ -1,33 +1,25 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"time"
 
+	"furador-de-coco/config"
 	"furador-de-coco/loadtest"
 )
 
-func main() {
-	// Flags do teste de carga
-	url := flag.String("url", "", "URL alvo para teste de carga (obrigatório)")
-	requests := flag.Int("requests", 1000, "Número total de requisições")
-	concurrency := flag.Int("concurrency", 50, "Número de workers concorrentes")
-	timeout := flag.Int("timeout", 30, "Timeout em segundos para cada requisição")
-	delay := flag.Int("delay", 0, "Delay em milissegundos entre requisições (0 = sem delay)")
-
-	flag.Parse()
-
-	if *url == "" {
-		fmt.Println("ERRO: URL é obrigatória")
-		fmt.Println("\nUso: go run cmd/loadtest/main.go -url <URL> [opções]")
-		fmt.Println("\nOpções:")
-		flag.PrintDefaults()
-		fmt.Println("\nAVISO: Use apenas em servidores próprios ou com autorização explícita!")
-		fmt.Println("Testes de carga não autorizados podem ser considerados ataques DoS.")
-		os.Exit(1)
-	}
+// runLoadTestCommand executa o teste de carga após confirmação, com a
+// sessão de login do scan quando -login é usado
+func runLoadTestCommand(ctx context.Context, args []string) {
+	var requests, concurrency, delay int
+	cfg := parseConfig("loadtest", config.FlagsTarget, args, func(fs *flag.FlagSet) {
+		fs.IntVar(&requests, "requests", 1000, "Número total de requisições")
+		fs.IntVar(&concurrency, "concurrency", 50, "Número de workers concorrentes")
+		fs.IntVar(&delay, "delay", 0, "Delay em milissegundos entre requisições (0 = sem delay)")
+	})
 
 	// Confirmação de segurança
 	fmt.Println("\n╔════════════════════════════════════════════════════════════╗")
@@ -48,30 +40,33 @@ func main() {
 	}
 
 	// Configura teste
-	config := loadtest.LoadTestConfig{
-		URL:              *url,
-		TotalRequests:    *requests,
-		Concurrency:      *concurrency,
-		Timeout:          time.Duration(*timeout) * time.Second,
-		DelayBetweenReqs: time.Duration(*delay) * time.Millisecond,
+	ltConfig := loadtest.LoadTestConfig{
+		URL:              cfg.URL,
+		TotalRequests:    requests,
+		Concurrency:      concurrency,
+		Timeout:          cfg.Timeout,
+		DelayBetweenReqs: time.Duration(delay) * time.Millisecond,
+	}
+	if cfg.UseLogin {
+		ltConfig.Client = newSessionClient(cfg)
 	}
 
 	// Limites de segurança
-	if config.TotalRequests > 100000 {
+	if ltConfig.TotalRequests > 100000 {
 		fmt.Println("AVISO: Limitando para 100.000 requisições por segurança")
-		config.TotalRequests = 100000
+		ltConfig.TotalRequests = 100000
 	}
-	if config.Concurrency > 500 {
+	if ltConfig.Concurrency > 500 {
 		fmt.Println("AVISO: Limitando para 500 workers por segurança")
-		config.Concurrency = 500
+		ltConfig.Concurrency = 500
 	}
-	if config.DelayBetweenReqs < 0 {
+	if ltConfig.DelayBetweenReqs < 0 {
 		fmt.Println("AVISO: Delay não pode ser negativo")
-		config.DelayBetweenReqs = 0
+		ltConfig.DelayBetweenReqs = 0
 	}
 
 	// Executa teste
-	result, err := loadtest.RunLoadTest(config)
+	result, err := loadtest.RunLoadTest(ctx, ltConfig)
 	if err != nil {
 		fmt.Printf("Erro ao executar teste: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"furador-de-coco/logger"
+)
+
+// command é um subcomando do furador
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, args []string)
+}
+
+var commands = []command{
+	{"scan", "Busca formulários e testa XSS, SQLi e as demais verificações selecionadas", runScanCommand},
+	{"advanced", "Executa todas as verificações do registro e os templates em paralelo", runAdvancedCommand},
+	{"headers", "Analisa headers de segurança e cookies do alvo", runHeadersCommand},
+	{"loadtest", "Teste de carga (apenas em servidores próprios ou autorizados)", runLoadTestCommand},
+	{"crawl", "Percorre o site e lista páginas, formulários e endpoints descobertos", runCrawlCommand},
+	{"report", "Gera relatórios TXT/HTML a partir de um relatório JSON salvo", runReportCommand},
+}
+
+func main() {
+	printBanner()
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	// O primeiro Ctrl-C cancela o contexto: nenhuma requisição nova é
+	// enviada e os relatórios saem com o que foi encontrado até ali. O
+	// segundo encerra na hora.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+		logger.Warn("Interrompido: finalizando e salvando resultados parciais (Ctrl-C de novo para sair)")
+	}()
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			cmd.run(ctx, os.Args[2:])
+			return
+		}
+	}
+
+	if name != "-h" && name != "-help" && name != "help" {
+		fmt.Printf("Subcomando desconhecido: %s\n\n", name)
+	}
+	usage()
+	os.Exit(1)
+}
+
+func usage() {
+	fmt.Println("Uso: furador <subcomando> [opções]")
+	fmt.Println("\nSubcomandos:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Println("\nUse furador <subcomando> -h para ver as opções de cada um")
+}
+
+func printBanner() {
+	banner := `
++===========================================+
+|     FURADOR DE COCO                      |
+|     Security Vulnerability Scanner        |
+|     v2.0 - Enhanced Edition              |
++===========================================+
+`
+	fmt.Println(banner)
+}
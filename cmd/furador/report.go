@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"furador-de-coco/config"
+	"furador-de-coco/logger"
+	"furador-de-coco/report"
+)
+
+// runReportCommand regenera os relatórios a partir de um relatório JSON
+// salvo por scan, advanced ou headers
+func runReportCommand(ctx context.Context, args []string) {
+	cfg := config.NewConfig()
+	fs := cfg.NewFlagSet("report", config.FlagsOutput)
+	input := fs.String("input", "relatorio.json", "Relatório JSON de entrada")
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	scanReport, err := report.LoadJSON(*input)
+	if err != nil {
+		logger.Fatal("Erro ao ler relatório: %v", err)
+	}
+	fmt.Printf("Relatório de %s: %d finding(s)\n", scanReport.TargetURL, len(scanReport.Findings))
+
+	saveReports(cfg, scanReport)
+	report.PrintRiskScore(scanReport.Findings)
+}
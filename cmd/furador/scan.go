@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"furador-de-coco/config"
+	"furador-de-coco/finding"
+	"furador-de-coco/logger"
+	"furador-de-coco/report"
+	"furador-de-coco/scanner"
+	"furador-de-coco/ui"
+)
+
+// runScanCommand busca os formulários do alvo e executa as verificações
+// selecionadas, com XSS e SQLi por formulário no worker pool
+func runScanCommand(ctx context.Context, args []string) {
+	cfg := parseConfig("scan", config.FlagsTarget|config.FlagsLimits|config.FlagsCrawl|config.FlagsOutput|config.FlagsChecks|config.FlagsResume, args, nil)
+
+	logger.Info("Iniciando scan em: %s", cfg.URL)
+	logger.Info("Workers: %d | Timeout: %v | Limite: %s",
+		cfg.Workers, cfg.Timeout, limitsSummary(cfg))
+
+	safety := setupScanner(cfg)
+
+	checks, err := selectChecks(cfg)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
+	logger.Info("Verificações: %s", checkList(checks))
+
+	// Configura HTTP client
+	httpClient := setupHTTPClient(cfg)
+
+	ctx, st := openState(ctx, cfg, "scan")
+
+	scanReport := &report.ScanReport{
+		StartTime:   st.Started(),
+		TargetURL:   cfg.URL,
+		SafetyLevel: safety.String(),
+	}
+
+	// Busca formulários
+	logger.Info("Buscando formulários...")
+	targets, err := loadTargets(ctx, cfg, httpClient, st)
+	if err != nil {
+		logger.Fatal("Erro ao buscar formulários: %v", err)
+	}
+	forms, endpoints := targets.Forms, targets.Endpoints
+	scanReport.Endpoints = endpoints
+
+	if len(forms) == 0 {
+		logger.Warn("Nenhum formulário encontrado em %s", cfg.URL)
+		// Sem endpoints descobertos nem teste de DOM XSS (que usa apenas a
+		// URL) não há nada para reportar
+		if len(endpoints) == 0 && !cfg.TestDOMXSS {
+			st.Remove()
+			os.Exit(0)
+		}
+	} else {
+		logger.Success("Encontrados %d formulário(s)", len(forms))
+	}
+
+	// XSS e SQLi rodam por formulário no worker pool, com detalhes no
+	// relatório; as demais verificações selecionadas rodam pelo registro
+	formChecks, otherChecks := splitFormChecks(checks)
+
+	var results []report.ScanResult
+	var checkResults scanner.CheckResults
+	if len(forms) > 0 && len(formChecks) > 0 {
+		results, checkResults = runScan(ctx, cfg, forms, httpClient, formChecks)
+	}
+
+	if len(otherChecks) > 0 {
+		logger.Info("Executando verificações: %s", checkList(otherChecks))
+		other := scanner.RunChecksParallel(ctx, otherChecks, cfg.URL, forms, httpClient, cfg.Workers)
+		scanner.PrintFindings(other.Findings)
+		checkResults.Merge(other)
+	}
+
+	if cfg.TestStoredXSS && len(forms) > 0 {
+		checkResults.Add(runStep(ctx, "stored-xss", func() scanner.ScanJobResult {
+			return runStoredXSS(ctx, cfg, forms, targets.Pages, httpClient)
+		}))
+	}
+
+	if cfg.TestDOMXSS {
+		checkResults.Add(runStep(ctx, "dom-xss", func() scanner.ScanJobResult {
+			return runDOMXSS(ctx, cfg, forms, targets.Pages)
+		}))
+	}
+
+	// Salva relatórios
+	scanReport.FormsScanned = len(forms)
+	scanReport.Results = results
+	finishReport(ctx, scanReport, checkResults)
+	saveReports(cfg, scanReport)
+	closeState(st, "scan", scanReport.Incomplete, len(scanReport.Inconclusive))
+
+	// Calcula e exibe score de risco
+	report.PrintRiskScore(checkResults.Findings)
+
+	if !scanReport.Incomplete {
+		logger.Success("Scan concluído com sucesso!")
+	}
+}
+
+// splitFormChecks separa XSS e SQLi, que têm resultados detalhados por
+// formulário no relatório, das demais verificações
+func splitFormChecks(checks []scanner.Check) (form map[string]bool, other []scanner.Check) {
+	form = map[string]bool{}
+	for _, c := range checks {
+		if c.ID() == "xss" || c.ID() == "sqli" {
+			form[c.ID()] = true
+		} else {
+			other = append(other, c)
+		}
+	}
+	return form, other
+}
+
+// runScan roda XSS e SQLi nos formulários e retorna o status por formulário
+// e os findings, testes inconclusivos e tempos dos testes. Campos e payloads
+// de todos os formulários são distribuídos entre os workers.
+func runScan(ctx context.Context, cfg *config.Config, forms []scanner.Form, httpClient *http.Client, formChecks map[string]bool) ([]report.ScanResult, scanner.CheckResults) {
+	logger.Info("Iniciando scan de vulnerabilidades...")
+
+	progressBar := ui.NewProgressBar(len(forms))
+
+	jobs := make([]scanner.ScanJob, len(forms))
+	for i, form := range forms {
+		jobs[i] = scanner.ScanJob{
+			Form:      form,
+			BaseURL:   formBaseURL(form, cfg.URL),
+			FormIndex: i,
+		}
+	}
+
+	// Os formulários terminam fora de ordem; os resultados são guardados
+	// pela posição para relatórios estáveis
+	byForm := make([]scanner.ScanJobResult, len(forms))
+	for jobResult := range scanner.ScanForms(ctx, jobs, httpClient, cfg.Workers, formChecks) {
+		byForm[jobResult.FormIndex] = jobResult
+		progressBar.Increment()
+	}
+	progressBar.Finish()
+
+	var results []report.ScanResult
+	var checkResults scanner.CheckResults
+	var timings []scanner.CheckTiming
+	for i, jobResult := range byForm {
+		baseURL := jobs[i].BaseURL
+
+		result := report.ScanResult{
+			URL:        baseURL,
+			FormAction: jobResult.Form.Action,
+			FormMethod: jobResult.Form.Method,
+			Timestamp:  time.Now(),
+			XSS:        jobResult.XSSVuln,
+			SQLi:       jobResult.SQLiVuln,
+		}
+
+		for _, xss := range jobResult.XSSResults {
+			if xss.Vulnerable {
+				logger.Warn("XSS detectado no formulário %d, campo '%s' (contexto %s)", i+1, xss.Field, xss.Context)
+				checkResults.Findings = append(checkResults.Findings, scanner.XSSFinding(jobResult.Form, baseURL, xss))
+			}
+		}
+		for _, sqli := range jobResult.SQLiResults {
+			if sqli.Vulnerable {
+				logger.Warn("SQLi detectado no formulário %d, campo '%s' (%s)", i+1, sqli.Field,
+					scanner.SQLiLabel(sqli.Type, sqli.DBMS))
+				checkResults.Findings = append(checkResults.Findings, scanner.SQLiFinding(jobResult.Form, baseURL, sqli))
+			}
+		}
+		for _, inc := range jobResult.Inconclusive {
+			result.Inconclusive = append(result.Inconclusive, inc.CheckID)
+		}
+		checkResults.Inconclusive = append(checkResults.Inconclusive, jobResult.Inconclusive...)
+		timings = append(timings, jobResult.Timings...)
+
+		// Formulário não testado (ou testado pela metade) por causa da
+		// interrupção: os findings valem, o status "seguro" não. Falhas dos
+		// testes já aparecem como inconclusivas.
+		if err := ctx.Err(); err != nil && errors.Is(jobResult.Error, err) {
+			continue
+		}
+
+		results = append(results, result)
+	}
+	checkResults.Timings = scanner.SummarizeTimings(timings)
+
+	return results, checkResults
+}
+
+// runStoredXSS envia payloads marcados a todos os formulários e revisita as
+// páginas de exibição configuradas (ou as páginas visitadas no scan). Falhas
+// nas requisições tornam o teste inconclusivo.
+func runStoredXSS(ctx context.Context, cfg *config.Config, forms []scanner.Form, pages []string, httpClient *http.Client) scanner.ScanJobResult {
+	displayURLs := cfg.DisplayURLs
+	if len(displayURLs) == 0 {
+		displayURLs = pages
+	}
+
+	logger.Info("Testando XSS armazenado (%d página(s) de exibição)...", len(displayURLs))
+
+	tracked, failures := scanner.TrackFailures(ctx)
+	var result scanner.ScanJobResult
+	for _, r := range scanner.TestStoredXSS(tracked, forms, cfg.URL, displayURLs, httpClient) {
+		logger.Warn("XSS armazenado: campo '%s' de %s exibido em %s", r.Field, r.SubmitURL, r.DisplayURL)
+		result.Findings = append(result.Findings, scanner.StoredXSSFinding(r))
+	}
+	if inc := failures.Inconclusive("stored-xss", cfg.URL, ""); inc != nil {
+		result.Inconclusive = []finding.Inconclusive{*inc}
+		result.Error = failures.CheckError("stored-xss", cfg.URL, "")
+	}
+	return result
+}
+
+// runDOMXSS verifica DOM XSS no navegador headless nas páginas visitadas e
+// nos formulários encontrados
+func runDOMXSS(ctx context.Context, cfg *config.Config, forms []scanner.Form, pages []string) scanner.ScanJobResult {
+	logger.Info("Testando DOM XSS no navegador headless (%d página(s))...", len(pages))
+
+	results, err := scanner.TestDOMXSS(ctx, pages, forms, cfg.Timeout)
+	if err != nil {
+		logger.Error("Erro no teste de DOM XSS: %v", err)
+		return scanner.ScanJobResult{Error: err}
+	}
+
+	var result scanner.ScanJobResult
+	for _, r := range results {
+		logger.Warn("DOM XSS em %s via %s (%s %s)", r.URL, r.Sink, r.Vector, r.Field)
+		result.Findings = append(result.Findings, scanner.DOMXSSFinding(r))
+	}
+	return result
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"furador-de-coco/auth"
+	"furador-de-coco/config"
+	"furador-de-coco/finding"
+	"furador-de-coco/logger"
+	"furador-de-coco/report"
+	"furador-de-coco/scanner"
+	"furador-de-coco/utils"
+)
+
+// parseConfig lê as flags do subcomando com os grupos informados. extra
+// registra flags próprias do subcomando antes do parse.
+func parseConfig(name string, groups int, args []string, extra func(fs *flag.FlagSet)) *config.Config {
+	cfg := config.NewConfig()
+	fs := cfg.NewFlagSet(name, groups)
+	if extra != nil {
+		extra(fs)
+	}
+	if err := cfg.Parse(fs, args); err != nil {
+		logger.Error("Erro ao carregar configuração: %v", err)
+		fmt.Printf("\nUso: furador %s -url <URL> [opções]\n", name)
+		fmt.Printf("Use furador %s -h para ver todas as opções disponíveis\n", name)
+		os.Exit(1)
+	}
+
+	// Configura logger
+	if cfg.Verbose {
+		logger.SetLevel(logger.DEBUG)
+	}
+
+	// Valida URL
+	validatedURL, err := utils.ValidateURL(cfg.URL)
+	if err != nil {
+		logger.Fatal("URL inválida: %v", err)
+	}
+	cfg.URL = validatedURL
+
+	return cfg
+}
+
+// setupScanner aplica o nível de segurança e a biblioteca de payloads da
+// configuração e retorna o nível escolhido
+func setupScanner(cfg *config.Config) scanner.SafetyLevel {
+	safety, err := scanner.ParseSafetyLevel(cfg.Safety)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
+	scanner.SetSafetyLevel(safety)
+	logger.Info("Nível de segurança dos payloads: %s", safety)
+
+	if cfg.PayloadsDir != "" {
+		lib, err := scanner.LoadLibrary(cfg.PayloadsDir)
+		if err != nil {
+			logger.Fatal("Erro ao carregar payloads: %v", err)
+		}
+		scanner.SetLibrary(lib)
+		logger.Info("Biblioteca de payloads: %d payloads e %d assinaturas (%s)",
+			len(lib.Payloads), len(lib.Signatures), cfg.PayloadsDir)
+	}
+
+	return safety
+}
+
+// setupHTTPClient cria o client compartilhado pelo scan, com login quando
+// configurado, os limites de -rps, -burst e -max-per-host e o backoff com
+// -retries no transport
+func setupHTTPClient(cfg *config.Config) *http.Client {
+	return utils.LimitClient(newSessionClient(cfg), utils.Limits{
+		RPS:        cfg.RPS,
+		Burst:      cfg.Burst,
+		MaxPerHost: cfg.MaxPerHost,
+		MaxRetries: cfg.MaxRetries,
+	})
+}
+
+// limitsSummary descreve os limites de requisições para o log
+func limitsSummary(cfg *config.Config) string {
+	rate := "sem limite de req/s"
+	if cfg.RPS > 0 {
+		rate = fmt.Sprintf("%g req/s (burst %d)", cfg.RPS, cfg.Burst)
+	}
+	if cfg.MaxPerHost > 0 {
+		return fmt.Sprintf("%s, %d por host", rate, cfg.MaxPerHost)
+	}
+	return rate
+}
+
+// newSessionClient cria um client sem limites de requisições, com login
+// quando configurado
+func newSessionClient(cfg *config.Config) *http.Client {
+	var httpClient *http.Client
+
+	if cfg.UseLogin {
+		logger.Info("Fazendo login...")
+
+		if err := utils.ValidateLoginFields(
+			cfg.LoginURL, cfg.UserField, cfg.PassField,
+			cfg.Username, cfg.Password); err != nil {
+			logger.Fatal("Campos de login inválidos: %v", err)
+		}
+
+		session, err := auth.Login(
+			cfg.LoginURL, cfg.UserField, cfg.PassField,
+			cfg.Username, cfg.Password)
+		if err != nil {
+			logger.Fatal("Erro ao fazer login: %v", err)
+		}
+
+		httpClient = session.Client
+		httpClient.Timeout = cfg.Timeout
+		logger.Success("Login realizado com sucesso")
+	} else {
+		httpClient = utils.NewHttpClientWithTimeout(cfg.Timeout)
+	}
+
+	return httpClient
+}
+
+// selectChecks aplica -checks/-exclude-checks ao registro, excluindo também as
+// verificações desligadas pelas opções -test-*
+func selectChecks(cfg *config.Config) ([]scanner.Check, error) {
+	exclude := append([]string(nil), cfg.ExcludeChecks...)
+	toggles := []struct {
+		id      string
+		enabled bool
+	}{
+		{"xss", cfg.TestXSS},
+		{"sqli", cfg.TestSQLi},
+		{"csrf", cfg.TestCSRF},
+		{"headers", cfg.TestHeaders},
+		{"cookies", cfg.TestCookies},
+	}
+	for _, t := range toggles {
+		if !t.enabled {
+			exclude = append(exclude, t.id)
+		}
+	}
+	return scanner.SelectChecks(cfg.Checks, exclude)
+}
+
+func checkList(checks []scanner.Check) string {
+	ids := make([]string, len(checks))
+	for i, c := range checks {
+		ids[i] = c.ID()
+	}
+	return strings.Join(ids, ", ")
+}
+
+// finishReport fecha o relatório com os findings ordenados por gravidade, as
+// verificações inconclusivas e o tempo por verificação, marcando-o como
+// incompleto quando o scan foi interrompido
+func finishReport(ctx context.Context, scanReport *report.ScanReport, results scanner.CheckResults) {
+	finding.Sort(results.Findings)
+	scanReport.EndTime = time.Now()
+	scanReport.Findings = results.Findings
+	scanReport.VulnsFound = len(results.Findings)
+	scanReport.Inconclusive = results.Inconclusive
+	if len(results.Inconclusive) > 0 {
+		logger.Warn("%d verificação(ões) inconclusiva(s): requisições falharam mesmo após novas tentativas", len(results.Inconclusive))
+		for _, inc := range results.Inconclusive {
+			logger.Debug("Inconclusivo: %s em %s %s (%d falha(s): %s)", inc.CheckID, inc.URL, inc.Parameter, inc.Failures, inc.Reason)
+		}
+	}
+	for _, t := range results.Timings {
+		logger.Debug("Tempo de %s: %v em %d alvo(s), %d com falha", t.CheckID, t.Duration.Round(time.Millisecond), t.Runs, t.Errors)
+		scanReport.CheckTimings = append(scanReport.CheckTimings, report.CheckTiming(t))
+	}
+	if ctx.Err() != nil {
+		scanReport.Incomplete = true
+		logger.Warn("Scan interrompido: os relatórios contêm apenas os resultados obtidos até aqui")
+	}
+}
+
+func saveReports(cfg *config.Config, scanReport *report.ScanReport) {
+	logger.Info("Gerando relatórios...")
+
+	if cfg.OutputTXT {
+		filename := filepath.Join(cfg.OutputDir, "relatorio.txt")
+		if err := report.SaveTxt(scanReport, filename); err != nil {
+			logger.Error("Erro ao salvar TXT: %v", err)
+		} else {
+			logger.Success("Relatório TXT salvo: %s", filename)
+		}
+	}
+
+	if cfg.OutputHTML {
+		filename := filepath.Join(cfg.OutputDir, "relatorio.html")
+		if err := report.SaveHTML(scanReport, filename); err != nil {
+			logger.Error("Erro ao salvar HTML: %v", err)
+		} else {
+			logger.Success("Relatório HTML salvo: %s", filename)
+		}
+	}
+
+	if cfg.OutputJSON {
+		filename := filepath.Join(cfg.OutputDir, "relatorio.json")
+		if err := report.SaveJSON(scanReport, filename); err != nil {
+			logger.Error("Erro ao salvar JSON: %v", err)
+		} else {
+			logger.Success("Relatório JSON salvo: %s", filename)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"furador-de-coco/config"
+	"furador-de-coco/logger"
+	"furador-de-coco/scanner"
+	"furador-de-coco/state"
+)
+
+// openState cria o arquivo de estado do comando em cfg.OutputDir ou, com
+// -resume, reabre o estado informado. No contexto retornado os jobs do pool
+// já concluídos são pulados e os novos são salvos no estado.
+func openState(ctx context.Context, cfg *config.Config, command string) (context.Context, *state.File) {
+	if cfg.Resume != "" {
+		st, err := state.Resume(cfg.Resume, command, cfg.Hash())
+		if err != nil {
+			logger.Fatal("Não foi possível retomar o scan: %v", err)
+		}
+		logger.Info("Retomando scan de %s: %d job(s) já concluído(s)", st.Path(), st.Completed())
+		return scanner.WithJournal(ctx, st), st
+	}
+
+	// O diretório de saída pode ainda não existir: os relatórios só são
+	// gravados no fim do scan
+	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+		logger.Fatal("Erro ao criar diretório de saída: %v", err)
+	}
+	st, err := state.Create(cfg.OutputDir, command, cfg.Hash(), cfg.URL)
+	if err != nil {
+		logger.Fatal("%v", err)
+	}
+	logger.Debug("Progresso salvo em %s", st.Path())
+	return scanner.WithJournal(ctx, st), st
+}
+
+// loadTargets usa os alvos salvos no estado de um scan retomado; senão
+// busca os formulários e salva o que foi descoberto. Uma descoberta
+// interrompida não é salva: o scan retomado a faz de novo.
+func loadTargets(ctx context.Context, cfg *config.Config, httpClient *http.Client, st *state.File) (*scanTargets, error) {
+	if saved := st.Targets(); saved != nil {
+		logger.Info("Usando os alvos salvos no estado (%d formulário(s), %d página(s))", len(saved.Forms), len(saved.Pages))
+		return &scanTargets{Forms: saved.Forms, Pages: saved.Pages, Endpoints: saved.Endpoints}, nil
+	}
+
+	targets, err := getForms(ctx, cfg, httpClient)
+	if err != nil || ctx.Err() != nil {
+		return targets, err
+	}
+	if err := st.SaveTargets(state.Targets{Forms: targets.Forms, Pages: targets.Pages, Endpoints: targets.Endpoints}); err != nil {
+		logger.Warn("%v", err)
+	}
+	return targets, nil
+}
+
+// runStep executa uma etapa do scan que não passa pelo pool (XSS
+// armazenado, DOM XSS), ou reaproveita o resultado salvo no estado
+func runStep(ctx context.Context, name string, run func() scanner.ScanJobResult) scanner.ScanJobResult {
+	key := scanner.StepKey(name)
+	if saved, ok := scanner.LookupJob(ctx, key); ok {
+		logger.Info("Etapa %s já concluída no scan retomado", name)
+		return saved
+	}
+	result := run()
+	scanner.RecordJob(ctx, key, result)
+	return result
+}
+
+// closeState apaga o estado de um scan concluído. Scans interrompidos ou
+// com verificações inconclusivas mantêm o estado, e o comando mostra como
+// continuar: com -resume só o que faltou roda de novo.
+func closeState(st *state.File, command string, incomplete bool, inconclusive int) {
+	if !incomplete && inconclusive == 0 {
+		if err := st.Remove(); err != nil {
+			logger.Debug("Erro ao apagar estado: %v", err)
+		}
+		return
+	}
+
+	if err := st.Close(); err != nil {
+		logger.Error("Progresso pode não ter sido salvo: %v", err)
+		return
+	}
+	logger.Info("Progresso salvo em %s", st.Path())
+	logger.Info("Para continuar, repita o comando com as mesmas opções: furador %s ... -resume %s", command, st.Path())
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"furador-de-coco/config"
+	"furador-de-coco/crawler"
+	"furador-de-coco/logger"
+	"furador-de-coco/report"
+	"furador-de-coco/scanner"
+)
+
+// scanTargets agrupa o que foi encontrado antes do scan
+type scanTargets struct {
+	Forms     []scanner.Form
+	Pages     []string // Páginas visitadas, revisitadas na busca por XSS armazenado
+	Endpoints []report.Endpoint
+}
+
+func getForms(ctx context.Context, cfg *config.Config, httpClient *http.Client) (*scanTargets, error) {
+	seeds := []string{cfg.URL}
+	var endpoints []report.Endpoint
+
+	if cfg.Discover {
+		logger.Info("Buscando robots.txt e sitemap.xml...")
+		for _, ep := range crawler.Discover(ctx, cfg.URL, httpClient) {
+			endpoints = append(endpoints, report.Endpoint{
+				URL:         ep.URL,
+				Source:      ep.Source,
+				Interesting: ep.Interesting,
+				Note:        ep.Note,
+			})
+			if ep.Interesting {
+				logger.Warn("Path bloqueado no robots.txt: %s", ep.URL)
+			}
+			if ep.URL != cfg.URL {
+				seeds = append(seeds, ep.URL)
+			}
+		}
+		if len(endpoints) > 0 {
+			logger.Success("Descobertos %d endpoint(s)", len(endpoints))
+		}
+	}
+
+	targets := &scanTargets{Endpoints: endpoints}
+	if cfg.Crawl {
+		result, err := crawlForms(ctx, cfg, httpClient, seeds, cfg.CrawlDepth)
+		if err != nil {
+			return nil, err
+		}
+		targets.Forms = result.Forms
+		targets.Pages = pageURLs(result)
+	} else {
+		if cfg.UseJS {
+			logger.Info("Usando modo headless (JavaScript)")
+			rendered, err := scanner.GetRenderedHTML(ctx, cfg.URL)
+			if err != nil {
+				return nil, err
+			}
+			targets.Forms = scanner.ParseFormsFromHTML(rendered)
+			for i := range targets.Forms {
+				targets.Forms[i].Page = cfg.URL
+			}
+		} else {
+			forms, err := scanner.GetForms(ctx, cfg.URL, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			targets.Forms = forms
+		}
+		targets.Pages = []string{cfg.URL}
+
+		// Sem crawl, as seeds extras são visitadas sem seguir seus links
+		if len(seeds) > 1 {
+			result, err := crawlForms(ctx, cfg, httpClient, seeds[1:], 0)
+			if err != nil {
+				logger.Warn("Erro ao visitar endpoints descobertos: %v", err)
+			} else {
+				targets.Forms = append(targets.Forms, result.Forms...)
+				targets.Pages = append(targets.Pages, pageURLs(result)...)
+			}
+		}
+	}
+
+	// Endpoints de API definidos explicitamente (JSON, multipart...)
+	if cfg.APIEndpoints != "" {
+		apiForms, err := scanner.LoadAPIEndpoints(cfg.APIEndpoints)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Carregados %d endpoint(s) de API de %s", len(apiForms), cfg.APIEndpoints)
+		targets.Forms = append(targets.Forms, apiForms...)
+	}
+
+	return targets, nil
+}
+
+// pageURLs retorna as URLs das páginas visitadas pelo crawler
+func pageURLs(result *crawler.Result) []string {
+	urls := make([]string, 0, len(result.Pages))
+	for _, page := range result.Pages {
+		urls = append(urls, page.URL)
+	}
+	return urls
+}
+
+func crawlForms(ctx context.Context, cfg *config.Config, httpClient *http.Client, seeds []string, depth int) (*crawler.Result, error) {
+	logger.Info("Percorrendo o site (profundidade %d, até %d páginas)...",
+		depth, cfg.CrawlMaxPages)
+
+	c, err := crawler.New(crawler.Config{
+		MaxDepth: depth,
+		MaxPages: cfg.CrawlMaxPages,
+		Include:  cfg.CrawlInclude,
+		Exclude:  cfg.CrawlExclude,
+	}, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.Crawl(ctx, seeds...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Success("Crawler visitou %d página(s)", len(result.Pages))
+	return result, nil
+}
+
+// formBaseURL retorna a página onde o formulário foi encontrado
+func formBaseURL(form scanner.Form, fallback string) string {
+	if form.Page != "" {
+		return form.Page
+	}
+	return fallback
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"furador-de-coco/scanner"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Println("Uso: go run cmd/validatepayloads/main.go <arquivo.json|diretório> ...")
+		fmt.Println("\nValida arquivos da biblioteca de payloads: JSON, campos obrigatórios,")
+		fmt.Println("categorias, níveis de segurança, bancos, regex, contextos dos breakouts")
+		fmt.Println("XSS e marcadores dos modelos das técnicas SQLi.")
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	errs := scanner.ValidateLibraryFiles(flag.Args()...)
+	for _, err := range errs {
+		fmt.Println("ERRO:", err)
+	}
+	if len(errs) > 0 {
+		fmt.Printf("\n%d erro(s) encontrado(s)\n", len(errs))
+		os.Exit(1)
+	}
+
+	fmt.Println("Biblioteca de payloads válida")
+}
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -17,7 +24,25 @@ type Config struct {
 	Verbose     bool
 	Workers     int
 	Timeout     time.Duration
-	RateLimit   time.Duration
+
+	// Limites de requisições aplicados ao client HTTP compartilhado:
+	// requisições por segundo (0 = sem limite), burst, requisições
+	// simultâneas por host (0 = sem limite) e novas tentativas após 429/503
+	RPS        float64
+	Burst      int
+	MaxPerHost int
+	MaxRetries int
+
+	// Crawler
+	Crawl         bool
+	Discover      bool
+	CrawlDepth    int
+	CrawlMaxPages int
+	CrawlInclude  []string
+	CrawlExclude  []string
+
+	// Arquivo JSON com endpoints de API a testar
+	APIEndpoints string
 
 	// Login
 	LoginURL   string
@@ -32,67 +57,181 @@ type Config struct {
 	OutputJSON  bool
 	OutputTXT   bool
 
+	// Arquivo de estado de um scan interrompido a retomar
+	Resume string
+
 	// Scan options
 	TestXSS     bool
 	TestSQLi    bool
 	TestCSRF    bool
 	TestHeaders bool
 	TestCookies bool
+
+	// Seleção de verificações do registro por ID ou categoria. Checks vazio
+	// seleciona todas; as opções -test-* acima excluem as desligadas.
+	Checks        []string
+	ExcludeChecks []string
+
+	// XSS armazenado: páginas revisitadas após o envio dos payloads
+	TestStoredXSS bool
+	DisplayURLs   []string
+
+	// DOM XSS verificado no navegador headless
+	TestDOMXSS bool
+
+	// Nível máximo dos payloads (safe, intrusive, destructive). Payloads
+	// destrutivos exigem também AllowDestructive.
+	Safety           string
+	AllowDestructive bool
+
+	// Diretório com arquivos JSON de payloads e assinaturas que complementam
+	// ou substituem (pelo id) a biblioteca embutida
+	PayloadsDir string
+
+	// Diretório com templates JSON que complementam os embutidos
+	TemplatesDir string
+
+	flags flagValues
 }
 
 // NewConfig cria uma nova configuração com valores padrão
 func NewConfig() *Config {
 	return &Config{
-		Workers:     5,
-		Timeout:     30 * time.Second,
-		RateLimit:   100 * time.Millisecond,
-		OutputDir:   ".",
-		OutputHTML:  true,
-		OutputJSON:  true,
-		OutputTXT:   true,
-		TestXSS:     true,
-		TestSQLi:    true,
-		TestCSRF:    true,
-		TestHeaders: true,
-		TestCookies: true,
+		Workers:       5,
+		Timeout:       30 * time.Second,
+		RPS:           20,
+		Burst:         5,
+		MaxPerHost:    10,
+		MaxRetries:    3,
+		Discover:      true,
+		CrawlDepth:    2,
+		CrawlMaxPages: 50,
+		OutputDir:     ".",
+		OutputHTML:    true,
+		OutputJSON:    true,
+		OutputTXT:     true,
+		TestXSS:       true,
+		TestSQLi:      true,
+		TestCSRF:      true,
+		TestHeaders:   true,
+		TestCookies:   true,
+		Safety:        "safe",
+		flags: flagValues{
+			timeoutSec: 30,
+		},
+	}
+}
+
+// Grupos de flags. Cada subcomando registra apenas os grupos que usa; os
+// valores padrão são os mesmos em todos.
+const (
+	// FlagsTarget: -url, -verbose, -timeout, -workers, -js e login
+	FlagsTarget = 1 << iota
+	// FlagsLimits: -rps, -burst, -max-per-host e -retries
+	FlagsLimits
+	// FlagsCrawl: crawler, robots.txt/sitemap.xml e endpoints de API
+	FlagsCrawl
+	// FlagsOutput: diretório e formatos dos relatórios
+	FlagsOutput
+	// FlagsChecks: verificações, nível de segurança, payloads e templates
+	FlagsChecks
+	// FlagsResume: -resume, para continuar um scan interrompido
+	FlagsResume
+)
+
+// flagValues guarda os valores das flags que precisam de conversão depois
+// do parse
+type flagValues struct {
+	timeoutSec    int
+	include       string
+	exclude       string
+	displayURLs   string
+	checks        string
+	excludeChecks string
+}
+
+// NewFlagSet cria o conjunto de flags de um subcomando com os grupos
+// informados. Flags próprias do subcomando podem ser adicionadas antes de
+// Parse.
+func (c *Config) NewFlagSet(name string, groups int) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+
+	if groups&FlagsTarget != 0 {
+		fs.StringVar(&c.URL, "url", "", "URL alvo para escanear (obrigatório)")
+		fs.BoolVar(&c.UseJS, "js", false, "Usar modo headless para renderizar JavaScript")
+		fs.BoolVar(&c.UseLogin, "login", false, "Fazer login antes de escanear")
+		fs.BoolVar(&c.Verbose, "verbose", false, "Modo verbose (logs detalhados)")
+		fs.IntVar(&c.Workers, "workers", 5, "Número de workers paralelos")
+		fs.IntVar(&c.flags.timeoutSec, "timeout", 30, "Timeout em segundos para requisições HTTP")
+
+		fs.StringVar(&c.LoginURL, "login-url", "", "URL da página de login")
+		fs.StringVar(&c.UserField, "user-field", "", "Nome do campo de usuário")
+		fs.StringVar(&c.PassField, "pass-field", "", "Nome do campo de senha")
+		fs.StringVar(&c.Username, "username", "", "Usuário para login")
+		fs.StringVar(&c.Password, "password", "", "Senha para login")
+	}
+
+	if groups&FlagsLimits != 0 {
+		fs.Float64Var(&c.RPS, "rps", 20, "Máximo de requisições por segundo, somando todos os workers (0 = sem limite)")
+		fs.IntVar(&c.Burst, "burst", 5, "Requisições que podem sair de uma vez após um período ocioso")
+		fs.IntVar(&c.MaxPerHost, "max-per-host", 10, "Máximo de requisições simultâneas por host (0 = sem limite)")
+		fs.IntVar(&c.MaxRetries, "retries", 3, "Novas tentativas de requisições idempotentes após 429/503 ou conexão derrubada")
+	}
+
+	if groups&FlagsCrawl != 0 {
+		fs.BoolVar(&c.Crawl, "crawl", false, "Percorrer o site (mesma origem) em busca de formulários")
+		fs.BoolVar(&c.Discover, "discover", true, "Usar robots.txt e sitemap.xml como seeds adicionais")
+		fs.IntVar(&c.CrawlDepth, "max-depth", 2, "Profundidade máxima do crawler")
+		fs.IntVar(&c.CrawlMaxPages, "max-pages", 50, "Número máximo de páginas visitadas pelo crawler")
+		fs.StringVar(&c.flags.include, "include", "", "Regex de paths a incluir no crawl (separados por vírgula)")
+		fs.StringVar(&c.flags.exclude, "exclude", "", "Regex de paths a excluir do crawl (separados por vírgula)")
+		fs.StringVar(&c.APIEndpoints, "api-endpoints", "", "Arquivo JSON com endpoints de API (url, method, encoding, headers, params)")
+	}
+
+	if groups&FlagsOutput != 0 {
+		fs.StringVar(&c.OutputDir, "output", ".", "Diretório para salvar relatórios")
+		fs.BoolVar(&c.OutputHTML, "html", true, "Gerar relatório HTML")
+		fs.BoolVar(&c.OutputJSON, "json", true, "Gerar relatório JSON")
+		fs.BoolVar(&c.OutputTXT, "txt", true, "Gerar relatório TXT")
+	}
+
+	if groups&FlagsChecks != 0 {
+		fs.BoolVar(&c.TestXSS, "test-xss", true, "Testar vulnerabilidades XSS")
+		fs.BoolVar(&c.TestSQLi, "test-sqli", true, "Testar vulnerabilidades SQLi")
+		fs.BoolVar(&c.TestCSRF, "test-csrf", true, "Testar proteção CSRF")
+		fs.BoolVar(&c.TestHeaders, "test-headers", true, "Testar headers de segurança")
+		fs.BoolVar(&c.TestCookies, "test-cookies", true, "Testar segurança de cookies")
+		fs.BoolVar(&c.TestStoredXSS, "test-stored-xss", false, "Testar XSS armazenado (grava payloads na aplicação)")
+		fs.BoolVar(&c.TestDOMXSS, "test-dom-xss", false, "Testar DOM XSS no navegador headless (requer Chrome)")
+		fs.StringVar(&c.flags.displayURLs, "display-urls", "", "URLs onde dados enviados são exibidos, separadas por vírgula (padrão: páginas visitadas)")
+
+		fs.StringVar(&c.Safety, "safety", "safe", "Nível máximo dos payloads: safe, intrusive ou destructive")
+		fs.BoolVar(&c.AllowDestructive, "allow-destructive", false, "Confirma o envio de payloads destrutivos (com -safety destructive)")
+		fs.StringVar(&c.PayloadsDir, "payloads", "", "Diretório com arquivos JSON de payloads e assinaturas (complementa os embutidos)")
+		fs.StringVar(&c.TemplatesDir, "templates", "", "Diretório com templates JSON de verificações (complementa os embutidos)")
+		fs.StringVar(&c.flags.checks, "checks", "", "Verificações a executar, por ID ou categoria, separadas por vírgula (padrão: todas)")
+		fs.StringVar(&c.flags.excludeChecks, "exclude-checks", "", "Verificações a não executar, por ID ou categoria, separadas por vírgula")
+	}
+
+	if groups&FlagsResume != 0 {
+		fs.StringVar(&c.Resume, "resume", "", "Arquivo de estado de um scan interrompido a continuar")
 	}
+
+	return fs
 }
 
-// ParseFlags configura e parseia as flags da linha de comando
-func (c *Config) ParseFlags() error {
-	flag.StringVar(&c.URL, "url", "", "URL alvo para escanear (obrigatório)")
-	flag.BoolVar(&c.UseJS, "js", false, "Usar modo headless para renderizar JavaScript")
-	flag.BoolVar(&c.UseLogin, "login", false, "Fazer login antes de escanear")
-	flag.BoolVar(&c.Verbose, "verbose", false, "Modo verbose (logs detalhados)")
-	flag.IntVar(&c.Workers, "workers", 5, "Número de workers paralelos")
-	
-	var timeoutSec int
-	flag.IntVar(&timeoutSec, "timeout", 30, "Timeout em segundos para requisições HTTP")
-	
-	var rateLimitMs int
-	flag.IntVar(&rateLimitMs, "rate-limit", 100, "Delay em ms entre requisições")
-
-	flag.StringVar(&c.LoginURL, "login-url", "", "URL da página de login")
-	flag.StringVar(&c.UserField, "user-field", "", "Nome do campo de usuário")
-	flag.StringVar(&c.PassField, "pass-field", "", "Nome do campo de senha")
-	flag.StringVar(&c.Username, "username", "", "Usuário para login")
-	flag.StringVar(&c.Password, "password", "", "Senha para login")
-
-	flag.StringVar(&c.OutputDir, "output", ".", "Diretório para salvar relatórios")
-	flag.BoolVar(&c.OutputHTML, "html", true, "Gerar relatório HTML")
-	flag.BoolVar(&c.OutputJSON, "json", true, "Gerar relatório JSON")
-	flag.BoolVar(&c.OutputTXT, "txt", true, "Gerar relatório TXT")
-
-	flag.BoolVar(&c.TestXSS, "test-xss", true, "Testar vulnerabilidades XSS")
-	flag.BoolVar(&c.TestSQLi, "test-sqli", true, "Testar vulnerabilidades SQLi")
-	flag.BoolVar(&c.TestCSRF, "test-csrf", true, "Testar proteção CSRF")
-	flag.BoolVar(&c.TestHeaders, "test-headers", true, "Testar headers de segurança")
-	flag.BoolVar(&c.TestCookies, "test-cookies", true, "Testar segurança de cookies")
-
-	flag.Parse()
-
-	c.Timeout = time.Duration(timeoutSec) * time.Second
-	c.RateLimit = time.Duration(rateLimitMs) * time.Millisecond
+// Parse parseia os argumentos do subcomando e valida a configuração
+func (c *Config) Parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c.Timeout = time.Duration(c.flags.timeoutSec) * time.Second
+	c.CrawlInclude = splitList(c.flags.include)
+	c.CrawlExclude = splitList(c.flags.exclude)
+	c.DisplayURLs = splitList(c.flags.displayURLs)
+	c.Checks = splitList(c.flags.checks)
+	c.ExcludeChecks = splitList(c.flags.excludeChecks)
 
 	return c.Validate()
 }
@@ -118,5 +257,125 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("número de workers deve ser <= 20")
 	}
 
+	if c.RPS < 0 {
+		return fmt.Errorf("requisições por segundo deve ser >= 0")
+	}
+
+	if c.Burst < 1 {
+		return fmt.Errorf("burst deve ser >= 1")
+	}
+
+	if c.MaxPerHost < 0 {
+		return fmt.Errorf("máximo de requisições por host deve ser >= 0")
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("número de novas tentativas deve ser >= 0")
+	}
+
+	if c.CrawlDepth < 0 {
+		return fmt.Errorf("profundidade do crawler deve ser >= 0")
+	}
+
+	if c.CrawlMaxPages < 1 {
+		return fmt.Errorf("número máximo de páginas deve ser >= 1")
+	}
+
+	switch strings.ToLower(c.Safety) {
+	case "safe", "intrusive":
+	case "destructive":
+		if !c.AllowDestructive {
+			return fmt.Errorf("-safety destructive pode apagar dados do alvo; confirme com -allow-destructive")
+		}
+	default:
+		return fmt.Errorf("nível de segurança inválido: %q (use safe, intrusive ou destructive)", c.Safety)
+	}
+
 	return nil
 }
+
+// Hash resume as opções que mudam o que o scan testa (alvo, descoberta,
+// login, verificações, payloads e templates). Dos payloads e templates
+// entra o conteúdo dos arquivos, não o caminho: editar um arquivo muda o
+// hash, mover o diretório não. Opções de desempenho e de saída, como
+// workers, limites de taxa e formatos de relatório, não entram: podem mudar
+// ao retomar um scan.
+func (c *Config) Hash() string {
+	data, _ := json.Marshal(struct {
+		URL                       string
+		UseJS, UseLogin           bool
+		LoginURL, Username        string
+		Crawl, Discover           bool
+		CrawlDepth, MaxPages      int
+		CrawlInclude, Exclude     []string
+		APIEndpoints              string
+		TestXSS, TestSQLi         bool
+		TestCSRF, TestHeaders     bool
+		TestCookies               bool
+		Checks, ExcludeChecks     []string
+		TestStoredXSS             bool
+		DisplayURLs               []string
+		TestDOMXSS                bool
+		Safety                    string
+		AllowDestructive          bool
+		Payloads, Templates       string
+	}{
+		c.URL,
+		c.UseJS, c.UseLogin,
+		c.LoginURL, c.Username,
+		c.Crawl, c.Discover,
+		c.CrawlDepth, c.CrawlMaxPages,
+		c.CrawlInclude, c.CrawlExclude,
+		c.APIEndpoints,
+		c.TestXSS, c.TestSQLi,
+		c.TestCSRF, c.TestHeaders,
+		c.TestCookies,
+		c.Checks, c.ExcludeChecks,
+		c.TestStoredXSS,
+		c.DisplayURLs,
+		c.TestDOMXSS,
+		strings.ToLower(c.Safety),
+		c.AllowDestructive,
+		filesDigest(c.PayloadsDir), filesDigest(c.TemplatesDir),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// filesDigest resume os arquivos .json de um diretório (ou o arquivo
+// informado), na mesma seleção usada ao carregar payloads e templates. Um
+// erro de leitura entra no resumo: o carregamento falha logo depois.
+func filesDigest(path string) string {
+	if path == "" {
+		return ""
+	}
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return "erro: " + err.Error()
+	} else if info.IsDir() {
+		files, _ = filepath.Glob(filepath.Join(path, "*.json"))
+		sort.Strings(files)
+	}
+
+	h := sha256.New()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "erro: " + err.Error()
+		}
+		fmt.Fprintf(h, "%s %d\n", filepath.Base(file), len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitList separa uma lista de valores separados por vírgula
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
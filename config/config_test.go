@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashPayloadFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(dir, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "custom.json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(dir, `{"payloads": []}`)
+	cfg := &Config{URL: "http://alvo", PayloadsDir: dir}
+	original := cfg.Hash()
+
+	// Outro diretório com o mesmo conteúdo mantém o hash
+	moved := t.TempDir()
+	write(moved, `{"payloads": []}`)
+	cfg.PayloadsDir = moved
+	if got := cfg.Hash(); got != original {
+		t.Errorf("hash mudou com o diretório movido: %s, esperado %s", got, original)
+	}
+
+	// Editar o arquivo muda o hash
+	write(moved, `{"payloads": [{"id": "x"}]}`)
+	if got := cfg.Hash(); got == original {
+		t.Error("hash não mudou com o arquivo de payloads editado")
+	}
+
+	// Arquivos fora da seleção carregada não entram
+	write(dir, `{"payloads": []}`)
+	cfg.PayloadsDir = dir
+	if err := os.WriteFile(filepath.Join(dir, "LEIAME.txt"), []byte("notas"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Hash(); got != original {
+		t.Errorf("hash mudou com arquivo que não é .json: %s, esperado %s", got, original)
+	}
+}
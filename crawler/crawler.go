@@ -0,0 +1,310 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"furador-de-coco/logger"
+	"furador-de-coco/scanner"
+
+	"golang.org/x/net/html"
+)
+
+// Tamanho máximo de página lida pelo crawler
+const maxBodySize = 5 << 20
+
+// Config configura os limites do crawler
+type Config struct {
+	MaxDepth int
+	MaxPages int
+	Include  []string // Regex de paths permitidos (vazio = todos)
+	Exclude  []string // Regex de paths ignorados
+}
+
+// Page representa uma página visitada pelo crawler
+type Page struct {
+	URL        string
+	Depth      int
+	StatusCode int
+	Forms      []scanner.Form
+}
+
+// Result armazena o resultado de um crawl
+type Result struct {
+	Pages []Page
+	Forms []scanner.Form
+}
+
+// Crawler percorre páginas de uma mesma origem em busca de formulários
+type Crawler struct {
+	cfg     Config
+	client  *http.Client
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+type queueItem struct {
+	url   string
+	depth int
+}
+
+// New cria um novo crawler
+func New(cfg Config, client *http.Client) (*Crawler, error) {
+	include, err := compilePatterns(cfg.Include)
+	if err != nil {
+		return nil, fmt.Errorf("padrão de include inválido: %w", err)
+	}
+	exclude, err := compilePatterns(cfg.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("padrão de exclude inválido: %w", err)
+	}
+
+	return &Crawler{
+		cfg:     cfg,
+		client:  client,
+		include: include,
+		exclude: exclude,
+	}, nil
+}
+
+// Crawl percorre o site a partir das seeds informadas. A origem do primeiro
+// seed define o escopo: links para outras origens são ignorados.
+func (c *Crawler) Crawl(ctx context.Context, seeds ...string) (*Result, error) {
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("nenhuma URL inicial informada")
+	}
+
+	origin, err := url.Parse(seeds[0])
+	if err != nil {
+		return nil, fmt.Errorf("URL inicial inválida: %w", err)
+	}
+
+	result := &Result{}
+	visited := make(map[string]bool)
+	var queue []queueItem
+
+	for i, seed := range seeds {
+		u, err := url.Parse(seed)
+		if err != nil || !sameOrigin(origin, u) {
+			continue
+		}
+		// A URL inicial é sempre visitada, mesmo fora dos filtros
+		if i > 0 && !c.inScope(u) {
+			continue
+		}
+		key := normalize(u)
+		if !visited[key] {
+			visited[key] = true
+			queue = append(queue, queueItem{url: key})
+		}
+	}
+
+	// Com o contexto cancelado o crawl para e retorna as páginas já visitadas
+	for len(queue) > 0 && len(result.Pages) < c.cfg.MaxPages && ctx.Err() == nil {
+		item := queue[0]
+		queue = queue[1:]
+
+		page, links, err := c.fetch(ctx, item)
+		if err != nil {
+			logger.Debug("Crawler: erro ao acessar %s: %v", item.url, err)
+			continue
+		}
+
+		logger.Debug("Crawler: %s (profundidade %d, %d formulário(s))",
+			page.URL, page.Depth, len(page.Forms))
+
+		result.Pages = append(result.Pages, *page)
+		result.Forms = append(result.Forms, page.Forms...)
+
+		if item.depth < c.cfg.MaxDepth {
+			for _, link := range links {
+				if !sameOrigin(origin, link) || !c.inScope(link) {
+					continue
+				}
+				key := normalize(link)
+				if visited[key] {
+					continue
+				}
+				visited[key] = true
+				queue = append(queue, queueItem{url: key, depth: item.depth + 1})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// fetch baixa uma página e extrai seus formulários e links
+func (c *Crawler) fetch(ctx context.Context, item queueItem) (*Page, []*url.URL, error) {
+	resp, err := get(ctx, c.client, item.url)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	page := &Page{
+		URL:        item.url,
+		Depth:      item.depth,
+		StatusCode: resp.StatusCode,
+	}
+
+	if !isHTML(resp.Header.Get("Content-Type")) {
+		return page, nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Usa a URL final (após redirects) como base dos links
+	pageURL := resp.Request.URL
+
+	page.Forms, err = scanner.ParseForms(bytes.NewReader(body), pageURL.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return page, extractLinks(doc, pageURL), nil
+}
+
+// inScope aplica os filtros de include/exclude ao path da URL
+func (c *Crawler) inScope(u *url.URL) bool {
+	path := u.RequestURI()
+
+	for _, re := range c.exclude {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+
+	if len(c.include) == 0 {
+		return true
+	}
+	for _, re := range c.include {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkAttrs mapeia os elementos seguidos pelo crawler ao atributo com a URL
+var linkAttrs = map[string]string{
+	"a":      "href",
+	"area":   "href",
+	"link":   "href",
+	"form":   "action",
+	"iframe": "src",
+	"frame":  "src",
+}
+
+// extractLinks retorna os links de um documento resolvidos contra a página
+// (ou contra <base href>, quando presente)
+func extractLinks(doc *html.Node, pageURL *url.URL) []*url.URL {
+	base := pageURL
+	var raw []string
+
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if n.Data == "base" {
+				if href := attr(n, "href"); href != "" {
+					if u, err := pageURL.Parse(href); err == nil {
+						base = u
+					}
+				}
+			}
+			if key, ok := linkAttrs[n.Data]; ok {
+				if val := strings.TrimSpace(attr(n, key)); val != "" {
+					raw = append(raw, val)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(doc)
+
+	var links []*url.URL
+	for _, r := range raw {
+		u, err := base.Parse(r)
+		if err != nil {
+			continue
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			continue
+		}
+		links = append(links, u)
+	}
+	return links
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+// get faz um GET que é interrompido com o cancelamento do contexto
+func get(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// normalize remove o fragmento para evitar visitar a mesma página duas vezes
+func normalize(u *url.URL) string {
+	n := *u
+	n.Fragment = ""
+	n.RawFragment = ""
+	if n.Path == "" {
+		n.Path = "/"
+	}
+	return n.String()
+}
+
+func isHTML(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	ct := strings.ToLower(contentType)
+	return strings.Contains(ct, "text/html") || strings.Contains(ct, "application/xhtml")
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
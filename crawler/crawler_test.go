@@ -0,0 +1,130 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+func newTestSite() *httptest.Server {
+	mux := http.NewServeMux()
+	page := func(path, body string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, body)
+		})
+	}
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><body>
+			<a href="/login">Login</a>
+			<a href="/admin/panel">Admin</a>
+			<a href="https://externo.example.com/">Externo</a>
+			<a href="mailto:contato@example.com">Email</a>
+			<iframe src="/frame"></iframe>
+		</body></html>`)
+	})
+	page("/login", `<form action="/session" method="post"><input name="user"></form>
+			<a href="/deep#top">Deep</a>`)
+	page("/admin/panel", `<form action="/admin/save"><input name="config"></form>`)
+	page("/frame", `<form action="/search"><input name="q"></form>`)
+	page("/deep", `<form action="/deep/form"><input name="x"></form>`)
+	return httptest.NewServer(mux)
+}
+
+func TestCrawl(t *testing.T) {
+	server := newTestSite()
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantPages []string
+		wantForms []string
+	}{
+		{
+			name:      "Profundidade 0 visita só a URL inicial",
+			cfg:       Config{MaxDepth: 0, MaxPages: 10},
+			wantPages: []string{"/"},
+			wantForms: nil,
+		},
+		{
+			name:      "Profundidade 1 segue links e iframes",
+			cfg:       Config{MaxDepth: 1, MaxPages: 10},
+			wantPages: []string{"/", "/admin/panel", "/frame", "/login"},
+			wantForms: []string{"/admin/save", "/search", "/session"},
+		},
+		{
+			name:      "Profundidade 2 segue links das páginas descobertas",
+			cfg:       Config{MaxDepth: 2, MaxPages: 10},
+			wantPages: []string{"/", "/admin/panel", "/deep", "/frame", "/login"},
+			wantForms: []string{"/admin/save", "/deep/form", "/search", "/session"},
+		},
+		{
+			name:      "Exclude remove paths",
+			cfg:       Config{MaxDepth: 1, MaxPages: 10, Exclude: []string{"^/admin"}},
+			wantPages: []string{"/", "/frame", "/login"},
+			wantForms: []string{"/search", "/session"},
+		},
+		{
+			name:      "Include restringe paths",
+			cfg:       Config{MaxDepth: 1, MaxPages: 10, Include: []string{"^/login"}},
+			wantPages: []string{"/", "/login"},
+			wantForms: []string{"/session"},
+		},
+		{
+			name:      "Limite de páginas",
+			cfg:       Config{MaxDepth: 2, MaxPages: 2},
+			wantPages: []string{"/", "/login"},
+			wantForms: []string{"/session"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.cfg, server.Client())
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := c.Crawl(context.Background(), server.URL+"/")
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+
+			var pages []string
+			for _, p := range result.Pages {
+				pages = append(pages, p.URL[len(server.URL):])
+			}
+			var forms []string
+			for _, f := range result.Forms {
+				if f.Page == "" {
+					t.Errorf("formulário %q sem página de origem", f.Action)
+				}
+				forms = append(forms, f.Action)
+			}
+			sort.Strings(pages)
+			sort.Strings(forms)
+
+			if fmt.Sprint(pages) != fmt.Sprint(tt.wantPages) {
+				t.Errorf("páginas = %v, want %v", pages, tt.wantPages)
+			}
+			if fmt.Sprint(forms) != fmt.Sprint(tt.wantForms) {
+				t.Errorf("formulários = %v, want %v", forms, tt.wantForms)
+			}
+		})
+	}
+}
+
+func TestNewInvalidPattern(t *testing.T) {
+	if _, err := New(Config{Include: []string{"("}}, http.DefaultClient); err == nil {
+		t.Error("New() deveria falhar com regex inválida")
+	}
+}
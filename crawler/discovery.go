@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"furador-de-coco/logger"
+)
+
+// Origens de um endpoint descoberto
+const (
+	SourceRobots  = "robots.txt"
+	SourceSitemap = "sitemap.xml"
+)
+
+// Endpoint representa uma URL descoberta via robots.txt ou sitemap
+type Endpoint struct {
+	URL         string
+	Source      string
+	Interesting bool // Paths bloqueados costumam apontar para áreas administrativas
+	Note        string
+}
+
+// Discover busca /robots.txt e /sitemap.xml da origem de baseURL e retorna
+// os endpoints da mesma origem listados neles
+func Discover(ctx context.Context, baseURL string, client *http.Client) []Endpoint {
+	origin, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	var endpoints []Endpoint
+	seen := make(map[string]bool)
+	add := func(raw string, ep Endpoint) {
+		u, err := origin.Parse(raw)
+		if err != nil || !sameOrigin(origin, u) {
+			return
+		}
+		ep.URL = normalize(u)
+		if seen[ep.URL] {
+			return
+		}
+		seen[ep.URL] = true
+		endpoints = append(endpoints, ep)
+	}
+
+	sitemaps := []string{(&url.URL{Scheme: origin.Scheme, Host: origin.Host, Path: "/sitemap.xml"}).String()}
+
+	robots, err := FetchRobots(ctx, baseURL, client)
+	if err != nil {
+		logger.Debug("robots.txt indisponível: %v", err)
+	} else {
+		for _, rule := range robots.Disallow {
+			if path := rulePath(rule); path != "" {
+				add(path, Endpoint{
+					Source:      SourceRobots,
+					Interesting: true,
+					Note:        "Disallow: " + rule,
+				})
+			}
+		}
+		for _, rule := range robots.Allow {
+			if path := rulePath(rule); path != "" {
+				add(path, Endpoint{Source: SourceRobots, Note: "Allow: " + rule})
+			}
+		}
+		sitemaps = append(sitemaps, robots.Sitemaps...)
+	}
+
+	fetched := make(map[string]bool)
+	for _, sitemap := range sitemaps {
+		if fetched[sitemap] {
+			continue
+		}
+		fetched[sitemap] = true
+
+		urls, err := FetchSitemap(ctx, sitemap, client)
+		if err != nil {
+			logger.Debug("Sitemap %s indisponível: %v", sitemap, err)
+			continue
+		}
+		for _, u := range urls {
+			add(u, Endpoint{Source: SourceSitemap})
+		}
+	}
+
+	return endpoints
+}
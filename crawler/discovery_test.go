@@ -0,0 +1,103 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRobots(t *testing.T) {
+	robots := ParseRobots(strings.NewReader(`
+User-agent: *
+Disallow: /admin/   # painel
+Disallow: /private*.php$
+Disallow:
+Allow: /public
+User-agent: Googlebot
+Disallow: /admin/
+Sitemap: https://example.com/sitemap_index.xml
+`))
+
+	if fmt.Sprint(robots.Disallow) != "[/admin/ /private*.php$]" {
+		t.Errorf("Disallow = %v", robots.Disallow)
+	}
+	if fmt.Sprint(robots.Allow) != "[/public]" {
+		t.Errorf("Allow = %v", robots.Allow)
+	}
+	if fmt.Sprint(robots.Sitemaps) != "[https://example.com/sitemap_index.xml]" {
+		t.Errorf("Sitemaps = %v", robots.Sitemaps)
+	}
+}
+
+func TestRulePath(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"/admin/", "/admin/"},
+		{"/private*.php$", "/private"},
+		{"/", ""},
+		{"*", ""},
+		{"/*.gif$", ""},
+	}
+
+	for _, tt := range tests {
+		if got := rulePath(tt.rule); got != tt.want {
+			t.Errorf("rulePath(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	var gzSitemap bytes.Buffer
+	gz := gzip.NewWriter(&gzSitemap)
+	gz.Write([]byte(`<urlset><url><loc>/from-gzip</loc></url></urlset>`))
+	gz.Close()
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "User-agent: *\nDisallow: /admin\nSitemap: %s/index.xml\n", server.URL)
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%s/about</loc></url>
+			<url><loc>https://outra-origem.example.com/x</loc></url></urlset>`, server.URL)
+	})
+	mux.HandleFunc("/index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex>
+			<sitemap><loc>%[1]s/pages.xml.gz</loc></sitemap>
+			<sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+		</sitemapindex>`, server.URL)
+	})
+	mux.HandleFunc("/pages.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-gzip")
+		w.Write(gzSitemap.Bytes())
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	endpoints := Discover(context.Background(), server.URL, server.Client())
+
+	got := make(map[string]Endpoint)
+	for _, ep := range endpoints {
+		got[strings.TrimPrefix(ep.URL, server.URL)] = ep
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("Discover() retornou %d endpoints, want 3: %v", len(got), endpoints)
+	}
+	if ep := got["/admin"]; !ep.Interesting || ep.Source != SourceRobots {
+		t.Errorf("/admin = %+v, want endpoint interessante do robots.txt", ep)
+	}
+	if ep := got["/about"]; ep.Interesting || ep.Source != SourceSitemap {
+		t.Errorf("/about = %+v, want endpoint do sitemap", ep)
+	}
+	if _, ok := got["/from-gzip"]; !ok {
+		t.Error("URL do sitemap gzip não foi descoberta")
+	}
+}
@@ -0,0 +1,88 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Robots armazena as regras relevantes de um robots.txt
+type Robots struct {
+	Allow    []string
+	Disallow []string
+	Sitemaps []string
+}
+
+// FetchRobots baixa e interpreta o /robots.txt da origem de baseURL
+func FetchRobots(ctx context.Context, baseURL string, client *http.Client) (*Robots, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+
+	resp, err := get(ctx, client, robotsURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("robots.txt retornou status %d", resp.StatusCode)
+	}
+
+	return ParseRobots(io.LimitReader(resp.Body, maxBodySize)), nil
+}
+
+// ParseRobots interpreta um robots.txt. As regras de todos os user-agents são
+// consideradas, já que o objetivo é descobrir paths e não respeitar o arquivo.
+func ParseRobots(r io.Reader) *Robots {
+	robots := &Robots{}
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" || seen[key+value] {
+			continue
+		}
+		seen[key+value] = true
+
+		switch key {
+		case "allow":
+			robots.Allow = append(robots.Allow, value)
+		case "disallow":
+			robots.Disallow = append(robots.Disallow, value)
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+	}
+
+	return robots
+}
+
+// rulePath converte uma regra do robots.txt em um path navegável, cortando
+// wildcards. Retorna "" para regras que cobrem o site inteiro.
+func rulePath(rule string) string {
+	if i := strings.IndexAny(rule, "*$"); i >= 0 {
+		rule = rule[:i]
+	}
+	if !strings.HasPrefix(rule, "/") || rule == "/" {
+		return ""
+	}
+	return rule
+}
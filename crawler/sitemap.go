@@ -0,0 +1,114 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Limite de sitemaps visitados a partir de um sitemap index
+const maxSitemaps = 50
+
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// FetchSitemap baixa um sitemap (ou sitemap index, seguido recursivamente) e
+// retorna todas as URLs listadas. Sitemaps compactados com gzip são aceitos.
+func FetchSitemap(ctx context.Context, sitemapURL string, client *http.Client) ([]string, error) {
+	var urls []string
+	visited := make(map[string]bool)
+	queue := []string{sitemapURL}
+
+	for len(queue) > 0 && len(visited) < maxSitemaps {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		data, err := fetchSitemapData(ctx, current, client)
+		if err != nil {
+			// Erro no sitemap principal é reportado; nos filhos é ignorado
+			if current == sitemapURL {
+				return nil, err
+			}
+			continue
+		}
+
+		pages, children, err := ParseSitemap(data)
+		if err != nil {
+			if current == sitemapURL {
+				return nil, err
+			}
+			continue
+		}
+		urls = append(urls, pages...)
+		queue = append(queue, children...)
+	}
+
+	return urls, nil
+}
+
+func fetchSitemapData(ctx context.Context, sitemapURL string, client *http.Client) ([]byte, error) {
+	resp, err := get(ctx, client, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s retornou status %d", sitemapURL, resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+}
+
+// ParseSitemap interpreta um sitemap ou sitemap index. Retorna as URLs de
+// páginas e as URLs de sitemaps filhos.
+func ParseSitemap(data []byte) (pages []string, sitemaps []string, err error) {
+	// Detecta gzip pelos magic bytes: servidores costumam entregar .xml.gz
+	// sem Content-Encoding, então o transport não descompacta sozinho
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer gz.Close()
+
+		data, err = io.ReadAll(io.LimitReader(gz, maxBodySize))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("sitemap inválido: %w", err)
+	}
+
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			pages = append(pages, loc)
+		}
+	}
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+
+	return pages, sitemaps, nil
+}
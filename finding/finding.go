@@ -0,0 +1,104 @@
+package finding
+
+import (
+	"sort"
+	"strings"
+)
+
+// Severity é a gravidade de um finding
+type Severity string
+
+const (
+	SeverityCritical Severity = "CRITICAL"
+	SeverityHigh     Severity = "HIGH"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityLow      Severity = "LOW"
+	SeverityInfo     Severity = "INFO"
+)
+
+var severityWeights = map[Severity]int{
+	SeverityCritical: 5,
+	SeverityHigh:     3,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+	SeverityInfo:     0,
+}
+
+// ParseSeverity converte nomes em qualquer caixa (ex.: "high"). Valores
+// desconhecidos viram INFO.
+func ParseSeverity(name string) Severity {
+	s := Severity(strings.ToUpper(strings.TrimSpace(name)))
+	if _, ok := severityWeights[s]; ok {
+		return s
+	}
+	return SeverityInfo
+}
+
+// Weight é o peso da gravidade no score de risco
+func (s Severity) Weight() int {
+	return severityWeights[s]
+}
+
+// Confidence indica o quanto a evidência sustenta o finding
+type Confidence string
+
+const (
+	// ConfidenceConfirmed: o efeito foi observado (breakout no DOM, marcador
+	// do UNION na resposta, JavaScript executado, header ausente)
+	ConfidenceConfirmed Confidence = "confirmed"
+	// ConfidenceFirm: indicador forte, como mensagem de erro do banco ou
+	// conteúdo de arquivo do servidor
+	ConfidenceFirm Confidence = "firm"
+	// ConfidenceTentative: heurística sujeita a falso positivo
+	ConfidenceTentative Confidence = "tentative"
+)
+
+// Finding é um problema encontrado por qualquer verificação, no formato
+// usado pelos relatórios e pelo score de risco
+type Finding struct {
+	CheckID     string     `json:"check_id"`
+	Title       string     `json:"title"`
+	Severity    Severity   `json:"severity"`
+	Confidence  Confidence `json:"confidence"`
+	CWE         string     `json:"cwe,omitempty"`
+	OWASP       string     `json:"owasp,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Method      string     `json:"method,omitempty"`
+	Parameter   string     `json:"parameter,omitempty"`
+	Payload     string     `json:"payload,omitempty"`
+	Evidence    string     `json:"evidence,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Request     string     `json:"request,omitempty"`  // Trecho da requisição enviada
+	Response    string     `json:"response,omitempty"` // Trecho da resposta
+	Remediation string     `json:"remediation,omitempty"`
+}
+
+// Sort ordena por gravidade (mais grave primeiro), mantendo a ordem original
+// entre findings da mesma gravidade
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity.Weight() > findings[j].Severity.Weight()
+	})
+}
+
+// Count conta os findings de uma gravidade
+func Count(findings []Finding, severity Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Inconclusive é uma verificação que não pôde ser concluída num alvo porque
+// requisições falharam depois das novas tentativas (limite de taxa, bloqueio,
+// timeout). Não conta como vulnerável nem como seguro.
+type Inconclusive struct {
+	CheckID   string `json:"check_id"`
+	URL       string `json:"url,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Failures  int    `json:"failures"` // Requisições que falharam
+	Reason    string `json:"reason"`   // Último erro
+}
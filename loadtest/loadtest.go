@@ -1,6 +1,7 @@
 package loadtest
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
@@ -19,6 +20,7 @@ type LoadTestResult struct {
 	MinResponseTime  time.Duration
 	MaxResponseTime  time.Duration
 	StatusCodeCounts map[int]int64
+	Incomplete       bool // Interrompido antes de enviar todas as requisições
 }
 
 // LoadTestConfig configura o teste de carga
@@ -28,10 +30,16 @@ type LoadTestConfig struct {
 	Concurrency      int
 	Timeout          time.Duration
 	DelayBetweenReqs time.Duration
+
+	// Client opcional compartilhado pelos workers (ex.: sessão de login).
+	// Sem ele cada worker cria um client com Timeout.
+	Client *http.Client
 }
 
-// RunLoadTest executa teste de carga com controle responsável
-func RunLoadTest(config LoadTestConfig) (*LoadTestResult, error) {
+// RunLoadTest executa teste de carga com controle responsável. Com o
+// contexto cancelado nenhuma requisição nova é enviada e o resultado parcial
+// é retornado com Incomplete.
+func RunLoadTest(ctx context.Context, config LoadTestConfig) (*LoadTestResult, error) {
 	fmt.Println("\n=== TESTE DE CARGA ===")
 	fmt.Printf("AVISO: Use apenas em servidores próprios ou com autorização!\n")
 	fmt.Printf("URL: %s\n", config.URL)
@@ -39,7 +47,11 @@ func RunLoadTest(config LoadTestConfig) (*LoadTestResult, error) {
 	fmt.Printf("Concorrência: %d workers\n", config.Concurrency)
 	fmt.Printf("Delay entre requisições: %v\n", config.DelayBetweenReqs)
 	fmt.Println("Iniciando em 3 segundos...")
-	time.Sleep(3 * time.Second)
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("teste cancelado antes de iniciar: %w", ctx.Err())
+	case <-time.After(3 * time.Second):
+	}
 
 	result := &LoadTestResult{
 		StatusCodeCounts: make(map[int]int64),
@@ -66,20 +78,39 @@ func RunLoadTest(config LoadTestConfig) (*LoadTestResult, error) {
 		go func(workerID int) {
 			defer wg.Done()
 
-			client := &http.Client{
-				Timeout: config.Timeout,
+			client := config.Client
+			if client == nil {
+				client = &http.Client{
+					Timeout: config.Timeout,
+				}
 			}
 
 			for range jobs {
 				// Delay controlado
 				if config.DelayBetweenReqs > 0 {
-					time.Sleep(config.DelayBetweenReqs)
+					select {
+					case <-ctx.Done():
+					case <-time.After(config.DelayBetweenReqs):
+					}
+				}
+				if ctx.Err() != nil {
+					continue
+				}
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.URL, nil)
+				if err != nil {
+					return
 				}
 
 				reqStart := time.Now()
-				resp, err := client.Get(config.URL)
+				resp, err := client.Do(req)
 				reqDuration := time.Since(reqStart)
 
+				// Requisições abortadas pelo cancelamento não entram na conta
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+
 				atomic.AddInt64(&requestCount, 1)
 
 				if err != nil {
@@ -117,7 +148,7 @@ func RunLoadTest(config LoadTestConfig) (*LoadTestResult, error) {
 	}
 
 	// Envia jobs
-	for i := 0; i < config.TotalRequests; i++ {
+	for i := 0; i < config.TotalRequests && ctx.Err() == nil; i++ {
 		jobs <- i
 	}
 	close(jobs)
@@ -132,6 +163,7 @@ func RunLoadTest(config LoadTestConfig) (*LoadTestResult, error) {
 	result.SuccessRequests = successCount
 	result.FailedRequests = failedCount
 	result.TotalDuration = totalDuration
+	result.Incomplete = ctx.Err() != nil
 	result.RequestsPerSec = float64(requestCount) / totalDuration.Seconds()
 
 	if len(responseTimes) > 0 {
@@ -148,6 +180,9 @@ func RunLoadTest(config LoadTestConfig) (*LoadTestResult, error) {
 // PrintResults imprime os resultados do teste
 func PrintResults(result *LoadTestResult) {
 	fmt.Println("\n\n=== RESULTADOS DO TESTE DE CARGA ===")
+	if result.Incomplete {
+		fmt.Println("AVISO: teste interrompido, resultados parciais")
+	}
 	fmt.Printf("Duração total: %v\n", result.TotalDuration)
 	fmt.Printf("Total de requisições: %d\n", result.TotalRequests)
 	fmt.Printf("Requisições bem-sucedidas: %d\n", result.SuccessRequests)
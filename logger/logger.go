@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"os"
 	"time"
 )
 
@@ -75,7 +76,8 @@ func Success(format string, args ...interface{}) {
 	log(SUCCESS, "OK   ", ColorGreen, format, args...)
 }
 
+// Fatal registra o erro e encerra o programa com status 1, sem stack trace
 func Fatal(format string, args ...interface{}) {
 	log(ERROR, "FATAL", ColorRed, format, args...)
-	panic(fmt.Sprintf(format, args...))
+	os.Exit(1)
 }
@@ -5,10 +5,12 @@ import (
 	"html"
 	"os"
 	"time"
+
+	"furador-de-coco/finding"
 )
 
 // SaveHTML salva o relatório em formato HTML com estilo
-func SaveHTML(results []ScanResult, filename string) error {
+func SaveHTML(scanReport *ScanReport, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -86,6 +88,16 @@ func SaveHTML(results []ScanResult, filename string) error {
         }
         .vuln-high { background: #fee; color: #c00; border: 1px solid #fcc; }
         .vuln-safe { background: #efe; color: #060; border: 1px solid #cfc; }
+        .incomplete {
+            background: #fff8e1;
+            color: #a60;
+            border: 1px solid #fe9;
+            border-radius: 4px;
+            padding: 12px;
+            margin-bottom: 20px;
+            font-weight: 600;
+        }
+        .vuln-medium { background: #fff8e1; color: #a60; border: 1px solid #fe9; }
         .details { 
             margin-top: 15px;
             padding: 15px;
@@ -112,6 +124,9 @@ func SaveHTML(results []ScanResult, filename string) error {
             border-radius: 3px;
             font-size: 12px;
         }
+        .endpoint { font-size: 13px; padding: 6px 0; border-bottom: 1px solid #f0f0f0; }
+        .endpoint .source { color: #666; margin-left: 8px; }
+        .endpoint-interesting { color: #c00; font-weight: 600; }
         .footer {
             text-align: center;
             padding: 20px;
@@ -129,12 +144,14 @@ func SaveHTML(results []ScanResult, filename string) error {
         </div>
         <div class="content">`)
 
+	results := scanReport.Results
+
 	// Summary
-	vulnCount := 0
-	for _, r := range results {
-		if r.XSS || r.SQLi {
-			vulnCount++
-		}
+	vulnCount := len(scanReport.Findings)
+	score, level := RiskScore(scanReport.Findings)
+
+	if scanReport.Incomplete {
+		file.WriteString(`<div class="incomplete">⚠ Scan interrompido: este relatório contém apenas os resultados obtidos até a interrupção.</div>`)
 	}
 
 	file.WriteString(`<div class="summary">
@@ -146,6 +163,14 @@ func SaveHTML(results []ScanResult, filename string) error {
             <h3>Vulnerabilidades Encontradas</h3>
             <div class="value">` + fmt.Sprintf("%d", vulnCount) + `</div>
         </div>
+        <div class="summary-card">
+            <h3>Nível de Segurança</h3>
+            <div class="value">` + html.EscapeString(scanReport.SafetyLevel) + `</div>
+        </div>
+        <div class="summary-card">
+            <h3>Score de Risco</h3>
+            <div class="value">` + fmt.Sprintf("%d", score) + ` <span class="form-meta">` + html.EscapeString(level) + `</span></div>
+        </div>
     </div>`)
 
 	// Resultados
@@ -164,77 +189,101 @@ func SaveHTML(results []ScanResult, filename string) error {
 		// Vulnerabilidades
 		if r.XSS {
 			file.WriteString(`<span class="vulnerability vuln-high">🚨 XSS VULNERÁVEL</span>`)
+		} else if r.inconclusive("xss") {
+			file.WriteString(`<span class="vulnerability vuln-medium">? XSS Inconclusivo</span>`)
 		} else {
 			file.WriteString(`<span class="vulnerability vuln-safe">✓ XSS Seguro</span>`)
 		}
 
 		if r.SQLi {
 			file.WriteString(`<span class="vulnerability vuln-high">🚨 SQLi VULNERÁVEL</span>`)
+		} else if r.inconclusive("sqli") {
+			file.WriteString(`<span class="vulnerability vuln-medium">? SQLi Inconclusivo</span>`)
 		} else {
 			file.WriteString(`<span class="vulnerability vuln-safe">✓ SQLi Seguro</span>`)
 		}
 
-		// Detalhes XSS
-		if len(r.XSSDetails) > 0 {
-			hasVuln := false
-			for _, detail := range r.XSSDetails {
-				if detail.Vulnerable {
-					hasVuln = true
-					break
-				}
+		file.WriteString(`</div></div>`)
+	}
+
+	// Findings de todas as verificações, do mais grave ao menos grave
+	if len(scanReport.Findings) > 0 {
+		file.WriteString(`<div class="form-result"><div class="form-title">Findings</div><div class="details">`)
+		for _, f := range scanReport.Findings {
+			file.WriteString(fmt.Sprintf(`
+            <div class="detail-item">
+                <span class="vulnerability %s">%s</span> <strong>%s</strong> <span class="form-meta">(%s, confiança %s)</span><br>
+                <strong>Classificação:</strong> %s %s<br>
+                <strong>URL:</strong> %s %s<br>`,
+				severityClass(f.Severity),
+				html.EscapeString(string(f.Severity)),
+				html.EscapeString(f.Title),
+				html.EscapeString(f.CheckID),
+				html.EscapeString(string(f.Confidence)),
+				html.EscapeString(f.CWE),
+				html.EscapeString(f.OWASP),
+				html.EscapeString(f.Method),
+				html.EscapeString(f.URL)))
+			if f.Parameter != "" {
+				file.WriteString(`<strong>Parâmetro:</strong> ` + html.EscapeString(f.Parameter) + `<br>`)
 			}
-			if hasVuln {
-				file.WriteString(`<div class="details"><h4>Detalhes de XSS:</h4>`)
-				for _, detail := range r.XSSDetails {
-					if detail.Vulnerable {
-						file.WriteString(fmt.Sprintf(`
-                        <div class="detail-item">
-                            <strong>Campo:</strong> %s<br>
-                            <strong>Payload:</strong> <span class="payload">%s</span><br>
-                            <strong>Descrição:</strong> %s
-                        </div>`,
-							html.EscapeString(detail.Field),
-							html.EscapeString(detail.Payload),
-							html.EscapeString(detail.Description)))
-					}
-				}
-				file.WriteString(`</div>`)
+			if f.Payload != "" {
+				file.WriteString(`<strong>Payload:</strong> <span class="payload">` + html.EscapeString(f.Payload) + `</span><br>`)
 			}
-		}
-
-		// Detalhes SQLi
-		if len(r.SQLiDetails) > 0 {
-			hasVuln := false
-			for _, detail := range r.SQLiDetails {
-				if detail.Vulnerable {
-					hasVuln = true
-					break
-				}
+			if f.Evidence != "" {
+				file.WriteString(`<strong>Evidência:</strong> ` + html.EscapeString(f.Evidence) + `<br>`)
+			}
+			if f.Description != "" {
+				file.WriteString(`<strong>Descrição:</strong> ` + html.EscapeString(f.Description) + `<br>`)
+			}
+			if f.Request != "" {
+				file.WriteString(`<strong>Requisição:</strong><pre class="payload">` + html.EscapeString(f.Request) + `</pre>`)
+			}
+			if f.Response != "" {
+				file.WriteString(`<strong>Resposta:</strong><pre class="payload">` + html.EscapeString(f.Response) + `</pre>`)
 			}
-			if hasVuln {
-				file.WriteString(`<div class="details"><h4>Detalhes de SQL Injection:</h4>`)
-				for _, detail := range r.SQLiDetails {
-					if detail.Vulnerable {
-						file.WriteString(fmt.Sprintf(`
-                        <div class="detail-item">
-                            <strong>Campo:</strong> %s<br>
-                            <strong>Payload:</strong> <span class="payload">%s</span><br>
-                            <strong>Tipo:</strong> %s<br>
-                            <strong>Indicador:</strong> %s
-                        </div>`,
-							html.EscapeString(detail.Field),
-							html.EscapeString(detail.Payload),
-							html.EscapeString(detail.Type),
-							html.EscapeString(detail.Indicator)))
-					}
-				}
-				file.WriteString(`</div>`)
+			if f.Remediation != "" {
+				file.WriteString(`<strong>Correção:</strong> ` + html.EscapeString(f.Remediation))
 			}
+			file.WriteString(`</div>`)
 		}
-
 		file.WriteString(`</div></div>`)
 	}
 
+	// Verificações que não puderam ser concluídas
+	if len(scanReport.Inconclusive) > 0 {
+		file.WriteString(`<div class="form-result"><div class="form-title">Verificações Inconclusivas</div>
+            <div class="form-meta">Requisições falharam mesmo após novas tentativas; estes alvos não foram verificados.</div>`)
+		for _, inc := range scanReport.Inconclusive {
+			file.WriteString(fmt.Sprintf(`
+            <div class="endpoint"><span class="vulnerability vuln-medium">%s</span>%s %s<span class="source">%d falha(s): %s</span></div>`,
+				html.EscapeString(inc.CheckID),
+				html.EscapeString(inc.URL),
+				html.EscapeString(inc.Parameter),
+				inc.Failures,
+				html.EscapeString(inc.Reason)))
+		}
+		file.WriteString(`</div>`)
+	}
+
+	// Endpoints descobertos
+	if len(scanReport.Endpoints) > 0 {
+		file.WriteString(`<div class="form-result"><div class="form-title">Endpoints Descobertos</div>`)
+		for _, ep := range scanReport.Endpoints {
+			class := "endpoint"
+			if ep.Interesting {
+				class += " endpoint-interesting"
+			}
+			file.WriteString(fmt.Sprintf(`
+            <div class="%s">%s<span class="source">%s %s</span></div>`,
+				class,
+				html.EscapeString(ep.URL),
+				html.EscapeString(ep.Source),
+				html.EscapeString(ep.Note)))
+		}
+		file.WriteString(`</div>`)
+	}
+
 	// Footer
 	file.WriteString(`
         </div>
@@ -247,3 +296,11 @@ func SaveHTML(results []ScanResult, filename string) error {
 
 	return nil
 }
+
+// severityClass escolhe o estilo do selo de gravidade
+func severityClass(s finding.Severity) string {
+	if s.Weight() >= finding.SeverityHigh.Weight() {
+		return "vuln-high"
+	}
+	return "vuln-medium"
+}
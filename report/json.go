@@ -2,14 +2,29 @@ package report
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 )
 
-func SaveJSON(results []ScanResult, filename string) error {
-	data, err := json.MarshalIndent(results, "", "  ")
+func SaveJSON(scanReport *ScanReport, filename string) error {
+	data, err := json.MarshalIndent(scanReport, "", "  ")
 	if err != nil {
 		return err
 	}
 
 	return os.WriteFile(filename, data, 0644)
 }
+
+// LoadJSON lê um relatório salvo por SaveJSON
+func LoadJSON(filename string) (*ScanReport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var scanReport ScanReport
+	if err := json.Unmarshal(data, &scanReport); err != nil {
+		return nil, fmt.Errorf("relatório JSON inválido em %s: %w", filename, err)
+	}
+	return &scanReport, nil
+}
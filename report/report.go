@@ -5,72 +5,79 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"furador-de-coco/finding"
 )
 
 // ScanResult representa o resultado de um scan de vulnerabilidades
 type ScanResult struct {
-	URL         string
-	FormAction  string
-	FormMethod  string
-	Timestamp   time.Time
-	XSS         bool
-	SQLi        bool
-	XSSDetails  []VulnDetail
-	SQLiDetails []VulnDetail
-}
+	URL        string
+	FormAction string
+	FormMethod string
+	Timestamp  time.Time
+	XSS        bool
+	SQLi       bool
 
-// VulnDetail armazena detalhes de uma vulnerabilidade
-type VulnDetail struct {
-	Vulnerable  bool
-	Payload     string
-	Description string
-	Field       string
-	Response    string
-	Type        string
-	Indicator   string
+	// IDs dos testes (xss, sqli) que não puderam ser concluídos neste
+	// formulário por falha nas requisições
+	Inconclusive []string
 }
 
 // ScanReport representa um relatório completo de scan
 type ScanReport struct {
-	StartTime       time.Time
-	EndTime         time.Time
-	TargetURL       string
-	FormsScanned    int
-	VulnsFound      int
-	Results         []ScanResult
-	SecurityHeaders []HeaderResult
-	CookieResults   []CookieResult
-	CSRFResults     []CSRFResult
+	StartTime    time.Time
+	EndTime      time.Time
+	TargetURL    string
+	SafetyLevel  string // Nível máximo dos payloads enviados
+	Incomplete   bool   // Scan interrompido antes do fim; resultados parciais
+	FormsScanned int
+	VulnsFound   int
+	Results      []ScanResult
+	Findings     []finding.Finding      // Todos os problemas, de todas as verificações
+	Inconclusive []finding.Inconclusive // Verificações que não puderam ser concluídas
+	CheckTimings []CheckTiming          // Tempo gasto por verificação
+	Endpoints    []Endpoint
 }
 
-// HeaderResult representa resultado de checagem de header
-type HeaderResult struct {
-	Name     string
-	Present  bool
-	Value    string
-	Severity string
-	Message  string
+// CheckTiming é o tempo gasto por uma verificação no scan, somando os alvos
+type CheckTiming struct {
+	CheckID  string
+	Runs     int
+	Errors   int
+	Duration time.Duration
 }
 
-// CookieResult representa resultado de checagem de cookie
-type CookieResult struct {
-	Name     string
-	Secure   bool
-	HTTPOnly bool
-	SameSite string
-	Issues   []string
+// Endpoint representa uma URL descoberta via robots.txt ou sitemap.xml
+type Endpoint struct {
+	URL         string
+	Source      string
+	Interesting bool
+	Note        string
 }
 
-// CSRFResult representa resultado de checagem CSRF
-type CSRFResult struct {
-	FormAction string
-	Protected  bool
-	TokenFound bool
-	Message    string
+// inconclusive indica se o teste do formulário não pôde ser concluído
+func (r ScanResult) inconclusive(checkID string) bool {
+	for _, id := range r.Inconclusive {
+		if id == checkID {
+			return true
+		}
+	}
+	return false
+}
+
+// status descreve o resultado de um teste do formulário no relatório texto
+func (r ScanResult) status(checkID string, vulnerable bool) string {
+	switch {
+	case vulnerable:
+		return "true"
+	case r.inconclusive(checkID):
+		return "inconclusivo (requisições falharam)"
+	}
+	return "false"
 }
 
 // SaveTxt salva o relatório em formato texto
-func SaveTxt(results []ScanResult, filename string) error {
+func SaveTxt(scanReport *ScanReport, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -78,42 +85,92 @@ func SaveTxt(results []ScanResult, filename string) error {
 	defer file.Close()
 
 	fmt.Fprintf(file, "=== RELATÓRIO DE VULNERABILIDADES ===\n")
-	fmt.Fprintf(file, "Gerado em: %s\n\n", time.Now().Format("02/01/2006 15:04:05"))
+	fmt.Fprintf(file, "Gerado em: %s\n", time.Now().Format("02/01/2006 15:04:05"))
+	fmt.Fprintf(file, "Alvo: %s\n", scanReport.TargetURL)
+	fmt.Fprintf(file, "Nível de segurança: %s\n", scanReport.SafetyLevel)
+	if scanReport.Incomplete {
+		fmt.Fprintf(file, "Status: INCOMPLETO (scan interrompido, resultados parciais)\n")
+	}
+	fmt.Fprintln(file)
 
-	for i, r := range results {
+	for i, r := range scanReport.Results {
 		fmt.Fprintf(file, "--- Formulário %d ---\n", i+1)
 		fmt.Fprintf(file, "URL: %s\n", r.URL)
 		fmt.Fprintf(file, "Action: %s\n", r.FormAction)
 		fmt.Fprintf(file, "Method: %s\n", r.FormMethod)
 		fmt.Fprintf(file, "Timestamp: %s\n", r.Timestamp.Format("15:04:05"))
 		fmt.Fprintf(file, "\nVulnerabilidades:\n")
-		fmt.Fprintf(file, "  XSS: %v\n", r.XSS)
-		fmt.Fprintf(file, "  SQLi: %v\n", r.SQLi)
-		
-		if len(r.XSSDetails) > 0 {
-			fmt.Fprintf(file, "\nDetalhes XSS:\n")
-			for _, detail := range r.XSSDetails {
-				if detail.Vulnerable {
-					fmt.Fprintf(file, "  - Campo: %s\n", detail.Field)
-					fmt.Fprintf(file, "    Payload: %s\n", detail.Payload)
-					fmt.Fprintf(file, "    Descrição: %s\n", detail.Description)
-				}
+		fmt.Fprintf(file, "  XSS: %s\n", r.status("xss", r.XSS))
+		fmt.Fprintf(file, "  SQLi: %s\n", r.status("sqli", r.SQLi))
+
+		fmt.Fprintln(file, "\n"+strings.Repeat("-", 50))
+	}
+
+	score, level := RiskScore(scanReport.Findings)
+	fmt.Fprintf(file, "\n=== FINDINGS (%d) ===\n", len(scanReport.Findings))
+	fmt.Fprintf(file, "Score de risco: %d pontos — Nível: %s\n", score, level)
+	for i, f := range scanReport.Findings {
+		fmt.Fprintf(file, "\n[%d] %s\n", i+1, f.Title)
+		fmt.Fprintf(file, "    Severidade: %s | Confiança: %s | Verificação: %s\n", f.Severity, f.Confidence, f.CheckID)
+		if f.CWE != "" || f.OWASP != "" {
+			fmt.Fprintf(file, "    Classificação: %s\n", strings.Trim(f.CWE+" | "+f.OWASP, " |"))
+		}
+		if f.URL != "" {
+			fmt.Fprintf(file, "    URL: %s %s\n", f.Method, f.URL)
+		}
+		if f.Parameter != "" {
+			fmt.Fprintf(file, "    Parâmetro: %s\n", f.Parameter)
+		}
+		if f.Payload != "" {
+			fmt.Fprintf(file, "    Payload: %s\n", f.Payload)
+		}
+		if f.Evidence != "" {
+			fmt.Fprintf(file, "    Evidência: %s\n", f.Evidence)
+		}
+		if f.Description != "" {
+			fmt.Fprintf(file, "    Descrição: %s\n", f.Description)
+		}
+		if f.Remediation != "" {
+			fmt.Fprintf(file, "    Correção: %s\n", f.Remediation)
+		}
+	}
+
+	if len(scanReport.Inconclusive) > 0 {
+		fmt.Fprintf(file, "\n=== VERIFICAÇÕES INCONCLUSIVAS (%d) ===\n", len(scanReport.Inconclusive))
+		fmt.Fprintf(file, "Requisições falharam mesmo após novas tentativas; estes alvos não foram verificados.\n")
+		for _, inc := range scanReport.Inconclusive {
+			fmt.Fprintf(file, "[?] %s em %s", inc.CheckID, inc.URL)
+			if inc.Parameter != "" {
+				fmt.Fprintf(file, " (parâmetro %s)", inc.Parameter)
 			}
+			fmt.Fprintf(file, " - %d falha(s): %s\n", inc.Failures, inc.Reason)
 		}
-		
-		if len(r.SQLiDetails) > 0 {
-			fmt.Fprintf(file, "\nDetalhes SQLi:\n")
-			for _, detail := range r.SQLiDetails {
-				if detail.Vulnerable {
-					fmt.Fprintf(file, "  - Campo: %s\n", detail.Field)
-					fmt.Fprintf(file, "    Payload: %s\n", detail.Payload)
-					fmt.Fprintf(file, "    Tipo: %s\n", detail.Type)
-					fmt.Fprintf(file, "    Indicador: %s\n", detail.Indicator)
-				}
+	}
+
+	if len(scanReport.CheckTimings) > 0 {
+		fmt.Fprintf(file, "\n=== TEMPO POR VERIFICAÇÃO ===\n")
+		for _, t := range scanReport.CheckTimings {
+			fmt.Fprintf(file, "%-20s %10s em %d alvo(s)", t.CheckID, t.Duration.Round(time.Millisecond), t.Runs)
+			if t.Errors > 0 {
+				fmt.Fprintf(file, ", %d com falha", t.Errors)
+			}
+			fmt.Fprintln(file)
+		}
+	}
+
+	if len(scanReport.Endpoints) > 0 {
+		fmt.Fprintf(file, "\n=== ENDPOINTS DESCOBERTOS ===\n")
+		for _, ep := range scanReport.Endpoints {
+			marker := " "
+			if ep.Interesting {
+				marker = "!"
+			}
+			fmt.Fprintf(file, "[%s] %s (%s)", marker, ep.URL, ep.Source)
+			if ep.Note != "" {
+				fmt.Fprintf(file, " - %s", ep.Note)
 			}
+			fmt.Fprintln(file)
 		}
-		
-		fmt.Fprintln(file, "\n" + strings.Repeat("-", 50))
 	}
 
 	return nil
@@ -1,27 +1,30 @@
 package report
 
-import "fmt"
+import (
+	"fmt"
 
-func PrintRiskScore(results []ScanResult) {
+	"furador-de-coco/finding"
+)
+
+// RiskScore soma os pesos das gravidades dos findings. Qualquer finding
+// CRITICAL já coloca o alvo no nível ALTO.
+func RiskScore(findings []finding.Finding) (int, string) {
 	score := 0
-	for _, r := range results {
-		if r.XSS {
-			score += 2
-		}
-		if r.SQLi {
-			score += 3
-		}
+	for _, f := range findings {
+		score += f.Severity.Weight()
 	}
 
-	var level string
 	switch {
-	case score >= 5:
-		level = " ALTO"
-	case score >= 2:
-		level = " MÉDIO"
+	case finding.Count(findings, finding.SeverityCritical) > 0 || score >= 10:
+		return score, "ALTO"
+	case score >= 4:
+		return score, "MÉDIO"
 	default:
-		level = " BAIXO"
+		return score, "BAIXO"
 	}
+}
 
+func PrintRiskScore(findings []finding.Finding) {
+	score, level := RiskScore(findings)
 	fmt.Printf("\nScore de risco: %d pontos — Nível: %s\n", score, level)
 }
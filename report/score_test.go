@@ -0,0 +1,35 @@
+package report
+
+import (
+	"testing"
+
+	"furador-de-coco/finding"
+)
+
+func TestRiskScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		severities []finding.Severity
+		wantScore  int
+		wantLevel  string
+	}{
+		{"Sem findings", nil, 0, "BAIXO"},
+		{"Apenas informativos", []finding.Severity{finding.SeverityInfo, finding.SeverityLow}, 1, "BAIXO"},
+		{"Headers e cookies", []finding.Severity{finding.SeverityMedium, finding.SeverityMedium, finding.SeverityLow}, 5, "MÉDIO"},
+		{"Um crítico basta", []finding.Severity{finding.SeverityCritical}, 5, "ALTO"},
+		{"Vários altos e médios", []finding.Severity{finding.SeverityHigh, finding.SeverityHigh, finding.SeverityMedium, finding.SeverityMedium}, 10, "ALTO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var findings []finding.Finding
+			for _, s := range tt.severities {
+				findings = append(findings, finding.Finding{Severity: s})
+			}
+			score, level := RiskScore(findings)
+			if score != tt.wantScore || level != tt.wantLevel {
+				t.Errorf("RiskScore() = %d %s, want %d %s", score, level, tt.wantScore, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ResolveAction resolve a action de um formulário em uma URL absoluta,
+// seguindo a resolução de referências da RFC 3986. A action é resolvida
+// contra o <base href> do documento, quando presente, e action vazia ou só
+// com fragmento ("#") aponta para a própria página.
+func ResolveAction(pageURL, baseHref, action string) (string, error) {
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("URL da página inválida: %w", err)
+	}
+	if !page.IsAbs() {
+		return "", fmt.Errorf("URL da página deve ser absoluta: %q", pageURL)
+	}
+
+	action = strings.TrimSpace(action)
+	if action == "" || strings.HasPrefix(action, "#") {
+		return stripFragment(page), nil
+	}
+
+	base := page
+	if href := strings.TrimSpace(baseHref); href != "" {
+		if b, err := page.Parse(href); err == nil {
+			base = b
+		}
+	}
+
+	target, err := base.Parse(action)
+	if err != nil {
+		return "", fmt.Errorf("action inválida %q: %w", action, err)
+	}
+
+	return stripFragment(target), nil
+}
+
+// TargetURL retorna a URL absoluta para onde o formulário é enviado. A página
+// de origem do formulário tem precedência; baseURL é usado quando ela não é
+// conhecida (ex.: HTML renderizado pelo modo headless).
+func (f Form) TargetURL(baseURL string) (string, error) {
+	page := f.Page
+	if page == "" {
+		page = baseURL
+	}
+	return ResolveAction(page, f.Base, f.Action)
+}
+
+func stripFragment(u *url.URL) string {
+	n := *u
+	n.Fragment = ""
+	n.RawFragment = ""
+	return n.String()
+}
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestResolveAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		baseHref string
+		action   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:   "Action relativa",
+			page:   "https://x.com/app/login",
+			action: "submit",
+			want:   "https://x.com/app/submit",
+		},
+		{
+			name:   "Action relativa com ..",
+			page:   "https://x.com/page/sub/form",
+			action: "../post",
+			want:   "https://x.com/page/post",
+		},
+		{
+			name:   "Action absoluta no path",
+			page:   "https://x.com/app/login",
+			action: "/submit",
+			want:   "https://x.com/submit",
+		},
+		{
+			name:   "Action com URL absoluta",
+			page:   "https://x.com/login",
+			action: "https://auth.x.com/submit",
+			want:   "https://auth.x.com/submit",
+		},
+		{
+			name:   "Action protocol-relative",
+			page:   "https://x.com/login",
+			action: "//cdn.x.com/upload",
+			want:   "https://cdn.x.com/upload",
+		},
+		{
+			name:   "Action só com query",
+			page:   "https://x.com/search?page=2",
+			action: "?q=1",
+			want:   "https://x.com/search?q=1",
+		},
+		{
+			name:   "Action vazia aponta para a página",
+			page:   "https://x.com/search?page=2",
+			action: "",
+			want:   "https://x.com/search?page=2",
+		},
+		{
+			name:   "Action # aponta para a página",
+			page:   "https://x.com/contato#form",
+			action: "#",
+			want:   "https://x.com/contato",
+		},
+		{
+			name:   "Fragmento é removido",
+			page:   "https://x.com/a",
+			action: "/b#secao",
+			want:   "https://x.com/b",
+		},
+		{
+			name:     "Base href relativo",
+			page:     "https://x.com/blog/post/1",
+			baseHref: "/app/",
+			action:   "comment",
+			want:     "https://x.com/app/comment",
+		},
+		{
+			name:     "Base href absoluto",
+			page:     "https://x.com/page",
+			baseHref: "https://static.x.com/v2/",
+			action:   "form.php",
+			want:     "https://static.x.com/v2/form.php",
+		},
+		{
+			name:     "Action vazia ignora base href",
+			page:     "https://x.com/page",
+			baseHref: "https://static.x.com/v2/",
+			action:   "",
+			want:     "https://x.com/page",
+		},
+		{
+			name:    "Página relativa",
+			page:    "/login",
+			action:  "submit",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveAction(tt.page, tt.baseHref, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormTargetURL(t *testing.T) {
+	forms := ParseFormsFromHTML(`<html><head><base href="/v2/"></head>
+		<body><form action="save"><input name="a"></form></body></html>`)
+	if len(forms) != 1 {
+		t.Fatalf("esperava 1 formulário, obteve %d", len(forms))
+	}
+
+	got, err := forms[0].TargetURL("https://x.com/admin/page")
+	if err != nil {
+		t.Fatalf("TargetURL() error = %v", err)
+	}
+	if want := "https://x.com/v2/save"; got != want {
+		t.Errorf("TargetURL() = %v, want %v", got, want)
+	}
+}
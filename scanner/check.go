@@ -0,0 +1,302 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"furador-de-coco/finding"
+)
+
+// Scope define sobre quais alvos uma verificação é executada
+type Scope int
+
+const (
+	// ScopeHost executa uma vez por alvo (headers, cookies)
+	ScopeHost Scope = iota
+	// ScopeURL executa uma vez por URL, variando parâmetros da query
+	ScopeURL
+	// ScopeForm executa uma vez por formulário
+	ScopeForm
+	// ScopeParameter executa uma vez por campo de cada formulário
+	ScopeParameter
+)
+
+var scopeNames = map[Scope]string{
+	ScopeHost:      "host",
+	ScopeURL:       "url",
+	ScopeForm:      "form",
+	ScopeParameter: "parameter",
+}
+
+func (s Scope) String() string {
+	return scopeNames[s]
+}
+
+// Target é o alvo de uma execução de verificação. Form e Parameter só são
+// preenchidos nos escopos de formulário e de parâmetro.
+type Target struct {
+	URL       string
+	Form      Form
+	Parameter string
+}
+
+// Check é uma verificação de segurança registrável
+type Check interface {
+	ID() string
+	Name() string
+	Category() string
+	Severity() finding.Severity
+	Scope() Scope
+	Run(ctx context.Context, target Target, client *http.Client) []finding.Finding
+}
+
+// checkFunc implementa Check a partir de uma função
+type checkFunc struct {
+	id       string
+	name     string
+	category string
+	severity finding.Severity
+	scope    Scope
+	run      func(ctx context.Context, target Target, client *http.Client) []finding.Finding
+}
+
+func (c *checkFunc) ID() string                 { return c.id }
+func (c *checkFunc) Name() string               { return c.name }
+func (c *checkFunc) Category() string           { return c.category }
+func (c *checkFunc) Severity() finding.Severity { return c.severity }
+func (c *checkFunc) Scope() Scope               { return c.scope }
+
+func (c *checkFunc) Run(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+	return c.run(ctx, target, client)
+}
+
+var registry []Check
+
+// Register adiciona uma verificação ao registro. IDs repetidos são erro de
+// programação.
+func Register(c Check) {
+	if LookupCheck(c.ID()) != nil {
+		panic(fmt.Sprintf("verificação registrada duas vezes: %s", c.ID()))
+	}
+	registry = append(registry, c)
+}
+
+// Checks retorna as verificações registradas, ordenadas por ID
+func Checks() []Check {
+	checks := append([]Check(nil), registry...)
+	sort.Slice(checks, func(i, j int) bool { return checks[i].ID() < checks[j].ID() })
+	return checks
+}
+
+// LookupCheck retorna a verificação com o ID informado ou nil
+func LookupCheck(id string) Check {
+	for _, c := range registry {
+		if c.ID() == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// SelectChecks filtra as verificações registradas. Cada seletor é um ID ou
+// uma categoria; include vazio seleciona todas. Seletores desconhecidos são
+// erro, para não rodar um scan sem a verificação pedida.
+func SelectChecks(include, exclude []string) ([]Check, error) {
+	known := map[string]bool{}
+	for _, c := range registry {
+		known[c.ID()] = true
+		known[c.Category()] = true
+	}
+	for _, sel := range append(append([]string(nil), include...), exclude...) {
+		if !known[sel] {
+			return nil, fmt.Errorf("verificação desconhecida: %q (disponíveis: %s)", sel, strings.Join(CheckIDs(), ", "))
+		}
+	}
+
+	matches := func(c Check, selectors []string) bool {
+		for _, sel := range selectors {
+			if sel == c.ID() || sel == c.Category() {
+				return true
+			}
+		}
+		return false
+	}
+
+	var selected []Check
+	for _, c := range Checks() {
+		if len(include) > 0 && !matches(c, include) {
+			continue
+		}
+		if matches(c, exclude) {
+			continue
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+// CheckIDs retorna os IDs das verificações registradas
+func CheckIDs() []string {
+	var ids []string
+	for _, c := range Checks() {
+		ids = append(ids, c.ID())
+	}
+	return ids
+}
+
+// ExpandTargets gera os alvos de um escopo a partir da URL alvo e dos
+// formulários encontrados
+func ExpandTargets(scope Scope, baseURL string, forms []Form) []Target {
+	switch scope {
+	case ScopeHost, ScopeURL:
+		return []Target{{URL: baseURL}}
+	}
+
+	var targets []Target
+	for _, form := range forms {
+		formURL := baseURL
+		if form.Page != "" {
+			formURL = form.Page
+		}
+		if scope == ScopeForm {
+			targets = append(targets, Target{URL: formURL, Form: form})
+			continue
+		}
+		for _, input := range form.Inputs {
+			if input.Type == "submit" || input.Name == "" {
+				continue
+			}
+			targets = append(targets, Target{URL: formURL, Form: form, Parameter: input.Name})
+		}
+	}
+	return targets
+}
+
+// CheckResults reúne o que as verificações produziram: findings, alvos
+// inconclusivos e o tempo gasto por verificação
+type CheckResults struct {
+	Findings     []finding.Finding
+	Inconclusive []finding.Inconclusive
+	Timings      []CheckTiming // Por verificação, somando os alvos
+}
+
+// Add acrescenta o resultado de um job
+func (r *CheckResults) Add(job ScanJobResult) {
+	r.Findings = append(r.Findings, job.Findings...)
+	r.Inconclusive = append(r.Inconclusive, job.Inconclusive...)
+	r.Timings = append(r.Timings, job.Timings...)
+}
+
+// Merge acrescenta os resultados de outra execução
+func (r *CheckResults) Merge(other CheckResults) {
+	r.Findings = append(r.Findings, other.Findings...)
+	r.Inconclusive = append(r.Inconclusive, other.Inconclusive...)
+	r.Timings = SummarizeTimings(append(r.Timings, other.Timings...))
+}
+
+// RunChecks executa as verificações sobre os alvos do escopo de cada uma.
+// Com o contexto cancelado nenhum alvo novo é iniciado. Alvos em que alguma
+// requisição falhou são retornados como inconclusivos.
+func RunChecks(ctx context.Context, checks []Check, baseURL string, forms []Form, client *http.Client) CheckResults {
+	var results CheckResults
+	for _, c := range checks {
+		for i, target := range ExpandTargets(c.Scope(), baseURL, forms) {
+			if ctx.Err() != nil {
+				results.Timings = SummarizeTimings(results.Timings)
+				return results
+			}
+			key := checkKey(c, i)
+			if saved, ok := LookupJob(ctx, key); ok {
+				results.Add(saved)
+				continue
+			}
+			var job ScanJobResult
+			runCheck(ctx, c, target, client, &job)
+			RecordJob(ctx, key, job)
+			results.Add(job)
+		}
+	}
+	results.Timings = SummarizeTimings(results.Timings)
+	return results
+}
+
+// RunChecksParallel executa as verificações como RunChecks, distribuindo
+// cada par (verificação, alvo) entre os workers do pool. Os findings saem na
+// mesma ordem de RunChecks.
+func RunChecksParallel(ctx context.Context, checks []Check, baseURL string, forms []Form, client *http.Client, workers int) CheckResults {
+	var jobs []ScanJob
+	var byJob []ScanJobResult
+	for _, c := range checks {
+		for i, target := range ExpandTargets(c.Scope(), baseURL, forms) {
+			job := ScanJob{Check: c, Target: target, key: checkKey(c, i), pos: len(byJob)}
+			saved, ok := LookupJob(ctx, job.key)
+			if !ok {
+				jobs = append(jobs, job)
+			}
+			byJob = append(byJob, saved)
+		}
+	}
+	if len(jobs) == 0 {
+		return collectChecks(byJob)
+	}
+
+	pool := NewWorkerPool(workers)
+	pool.Start(ctx, client)
+	go func() {
+		for _, job := range jobs {
+			if ctx.Err() != nil {
+				break
+			}
+			pool.Submit(job)
+		}
+		pool.Close()
+	}()
+
+	for jobResult := range pool.Results() {
+		byJob[jobResult.pos] = jobResult
+	}
+	return collectChecks(byJob)
+}
+
+// collectChecks junta os resultados dos jobs na ordem recebida
+func collectChecks(jobs []ScanJobResult) CheckResults {
+	var results CheckResults
+	for _, r := range jobs {
+		results.Add(r)
+	}
+	results.Timings = SummarizeTimings(results.Timings)
+	return results
+}
+
+// PrintFindings imprime os findings na ordem recebida
+func PrintFindings(findings []finding.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("\n[+] Nenhuma vulnerabilidade detectada")
+		return
+	}
+
+	fmt.Println("\n[!!!] VULNERABILIDADES DETECTADAS:")
+	fmt.Println(strings.Repeat("=", 80))
+
+	for i, f := range findings {
+		fmt.Printf("\n[%d] %s - Severidade: %s, confiança: %s (%s)\n", i+1, f.Title, f.Severity, f.Confidence, f.CheckID)
+		fmt.Printf("    Descrição: %s\n", f.Description)
+		if f.URL != "" {
+			fmt.Printf("    URL: %s\n", f.URL)
+		}
+		if f.Parameter != "" {
+			fmt.Printf("    Parâmetro: %s\n", f.Parameter)
+		}
+		if f.Evidence != "" {
+			fmt.Printf("    Evidência: %s\n", f.Evidence)
+		}
+		if f.Payload != "" {
+			fmt.Printf("    Payload: %s\n", f.Payload)
+		}
+	}
+
+	fmt.Println(strings.Repeat("=", 80))
+}
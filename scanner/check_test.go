@@ -0,0 +1,311 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"furador-de-coco/finding"
+)
+
+func TestSelectChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    string
+		wantErr bool
+	}{
+		{name: "Por ID", include: []string{"sqli", "xss"}, want: "sqli,xss"},
+		{name: "Por categoria", include: []string{"file"}, want: "lfi,traversal"},
+		{name: "Categoria menos um ID", include: []string{"injection"}, exclude: []string{"xxe"}, want: "cmdi,sqli,xss"},
+		{name: "Desconhecida", include: []string{"rce"}, wantErr: true},
+		{name: "Exclusão desconhecida", exclude: []string{"xs"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := SelectChecks(tt.include, tt.exclude)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, wantErr %v", err, tt.wantErr)
+			}
+			var ids []string
+			for _, c := range checks {
+				ids = append(ids, c.ID())
+			}
+			if got := strings.Join(ids, ","); !tt.wantErr && got != tt.want {
+				t.Errorf("SelectChecks() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTargets(t *testing.T) {
+	forms := []Form{
+		{Action: "/login", Method: "POST", Page: "http://alvo/entrar", Inputs: []Input{
+			{Name: "user", Type: "text"}, {Name: "pass", Type: "password"}, {Name: "ok", Type: "submit"},
+		}},
+		{Action: "/busca", Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}},
+	}
+
+	tests := []struct {
+		scope Scope
+		want  []string
+	}{
+		{ScopeHost, []string{"http://alvo/"}},
+		{ScopeForm, []string{"http://alvo/entrar", "http://alvo/"}},
+		{ScopeParameter, []string{"http://alvo/entrar user", "http://alvo/entrar pass", "http://alvo/ q"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, target := range ExpandTargets(tt.scope, "http://alvo/", forms) {
+			got = append(got, strings.TrimSpace(target.URL+" "+target.Parameter))
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("ExpandTargets(%s) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sessao", Value: "1", HttpOnly: true})
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	checks, err := SelectChecks([]string{"cookies", "csrf"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forms := []Form{
+		{Action: "/a", Method: "POST", Inputs: []Input{{Name: "csrf_token", Type: "hidden"}}},
+		{Action: "/b", Method: "POST", Inputs: []Input{{Name: "nome", Type: "text"}}},
+		{Action: "/busca", Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}},
+	}
+
+	want := []string{
+		"cookies: Cookie sessao - falta Secure, SameSite",
+		"csrf: Formulário sem token CSRF - campos: nome",
+	}
+	sequential := RunChecks(context.Background(), checks, server.URL, forms, server.Client())
+	parallel := RunChecksParallel(context.Background(), checks, server.URL, forms, server.Client(), 3)
+	runs := map[string][]finding.Finding{
+		"RunChecks":         sequential.Findings,
+		"RunChecksParallel": parallel.Findings,
+	}
+	for name, findings := range runs {
+		var got []string
+		for _, f := range findings {
+			got = append(got, f.CheckID+": "+f.Title+" - "+f.Evidence)
+			if f.CheckID == "csrf" && f.Confidence != finding.ConfidenceTentative {
+				t.Errorf("%s: confiança do CSRF = %s, want %s", name, f.Confidence, finding.ConfidenceTentative)
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s:\n%s\nwant:\n%s", name, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+func TestRunChecksAdvancedParameter(t *testing.T) {
+	defer SetSafetyLevel(CurrentSafetyLevel())
+	SetSafetyLevel(SafetyIntrusive)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("host"), "whoami") {
+			w.Write([]byte("www-data\n"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	checks, err := SelectChecks([]string{"cmdi"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forms := []Form{{Action: "/ping", Method: "GET", Inputs: []Input{
+		{Name: "nome", Type: "text"},
+		{Name: "host", Type: "text"},
+	}}}
+
+	result := RunChecks(context.Background(), checks, server.URL, forms, server.Client())
+	if len(result.Findings) == 0 {
+		t.Fatal("nenhum achado de injeção de comandos")
+	}
+	for _, f := range result.Findings {
+		if f.Parameter != "host" {
+			t.Errorf("parâmetro = %q, want %q (%s)", f.Parameter, "host", f.Payload)
+		}
+	}
+}
+
+func TestRunChecksParallelCanceled(t *testing.T) {
+	// O servidor só responde quando o cliente desiste da requisição
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	checks, err := SelectChecks([]string{"headers", "cookies", "csrf"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forms := []Form{{Action: "/b", Method: "POST", Inputs: []Input{{Name: "nome", Type: "text"}}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := RunChecksParallel(ctx, checks, server.URL, forms, server.Client(), 1)
+	findings, inconclusive := results.Findings, results.Inconclusive
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("RunChecksParallel levou %v após o cancelamento", elapsed)
+	}
+	for _, f := range findings {
+		if f.CheckID != "csrf" {
+			t.Errorf("finding inesperado após cancelamento: %s", f.CheckID)
+		}
+	}
+	// Cancelamento deixa o relatório incompleto, não as verificações
+	// inconclusivas
+	if len(inconclusive) > 0 {
+		t.Errorf("inconclusivos após cancelamento: %v", inconclusive)
+	}
+}
+
+func TestRunChecksInconclusive(t *testing.T) {
+	// O servidor derruba todas as conexões sem responder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	checks, err := SelectChecks([]string{"headers", "cookies"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := RunChecks(context.Background(), checks, server.URL, nil, server.Client())
+	if len(results.Findings) > 0 {
+		t.Errorf("findings sem resposta do alvo: %v", results.Findings)
+	}
+	var got []string
+	for _, inc := range results.Inconclusive {
+		got = append(got, inc.CheckID)
+		if inc.Failures == 0 || inc.Reason == "" {
+			t.Errorf("inconclusivo sem falhas registradas: %+v", inc)
+		}
+	}
+	if strings.Join(got, ",") != "cookies,headers" {
+		t.Errorf("inconclusivos = %v, esperado cookies e headers", got)
+	}
+	for _, timing := range results.Timings {
+		if timing.Runs != 1 || timing.Errors != 1 {
+			t.Errorf("tempo de %s = %+v, esperado 1 execução com falha", timing.CheckID, timing)
+		}
+	}
+}
+
+func TestWorkerPoolCheckErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	checks := []Check{
+		&checkFunc{id: "ok", scope: ScopeHost, run: func(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+			return nil
+		}},
+		&checkFunc{id: "panic", scope: ScopeHost, run: func(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+			var form *Form
+			return []finding.Finding{{Title: form.Action}}
+		}},
+	}
+
+	pool := NewWorkerPool(2)
+	pool.Start(context.Background(), server.Client())
+	go func() {
+		for _, c := range checks {
+			pool.Submit(ScanJob{Check: c, Target: Target{URL: server.URL}})
+		}
+		pool.Close()
+	}()
+
+	errs := map[string]error{}
+	for r := range pool.Results() {
+		if len(r.Timings) != 1 {
+			t.Fatalf("tempos = %+v, esperado um por job", r.Timings)
+		}
+		errs[r.Timings[0].CheckID] = r.Error
+	}
+
+	if err := errs["ok"]; err != nil {
+		t.Errorf("erro na verificação sem falhas: %v", err)
+	}
+	var checkErr *CheckError
+	if !errors.As(errs["panic"], &checkErr) || checkErr.CheckID != "panic" || !strings.Contains(checkErr.Error(), "panic") {
+		t.Errorf("erro do panic = %v, esperado CheckError", errs["panic"])
+	}
+}
+
+// mapJournal guarda os jobs em memória
+type mapJournal struct {
+	mu   sync.Mutex
+	jobs map[string]ScanJobResult
+}
+
+func (j *mapJournal) Lookup(key string) (ScanJobResult, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	r, ok := j.jobs[key]
+	return r, ok
+}
+
+func (j *mapJournal) Record(key string, result ScanJobResult) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jobs[key] = result
+}
+
+func TestRunChecksJournal(t *testing.T) {
+	var runs atomic.Int32
+	check := &checkFunc{id: "conta", scope: ScopeForm, run: func(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+		runs.Add(1)
+		return []finding.Finding{{CheckID: "conta", URL: target.URL, Parameter: target.Form.Action}}
+	}}
+	forms := []Form{{Action: "/a"}, {Action: "/b"}, {Action: "/c"}}
+
+	// O primeiro formulário já foi verificado no scan interrompido
+	journal := &mapJournal{jobs: map[string]ScanJobResult{
+		checkKey(check, 0): {Findings: []finding.Finding{{CheckID: "conta", Parameter: "salvo"}}},
+	}}
+	ctx := WithJournal(context.Background(), journal)
+
+	results := RunChecksParallel(ctx, []Check{check}, "http://alvo", forms, http.DefaultClient, 2)
+
+	if got := runs.Load(); got != 2 {
+		t.Errorf("verificação executada %d vez(es), esperado 2", got)
+	}
+	var got []string
+	for _, f := range results.Findings {
+		got = append(got, f.Parameter)
+	}
+	if strings.Join(got, ",") != "salvo,/b,/c" {
+		t.Errorf("findings = %v, esperado o salvo seguido dos novos", got)
+	}
+	if len(journal.jobs) != 3 {
+		t.Errorf("journal com %d job(s), esperado 3", len(journal.jobs))
+	}
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+
+	"furador-de-coco/finding"
+)
+
+// Verificações embutidas. Para adicionar uma nova basta registrá-la aqui
+// (ou em init de outro arquivo do pacote); os binários a executam via
+// SelectChecks/RunChecks.
+func init() {
+	Register(&checkFunc{
+		id: "xss", name: "Cross-Site Scripting (XSS)", category: "injection",
+		severity: finding.SeverityHigh, scope: ScopeParameter, run: runXSSCheck,
+	})
+	Register(&checkFunc{
+		id: "sqli", name: "SQL Injection", category: "injection",
+		severity: finding.SeverityCritical, scope: ScopeForm, run: runSQLiCheck,
+	})
+	Register(&checkFunc{
+		id: "cmdi", name: "Command Injection", category: "injection",
+		severity: finding.SeverityCritical, scope: ScopeForm, run: formCheck("cmdi", TestCommandInjection),
+	})
+	Register(&checkFunc{
+		id: "xxe", name: "XML External Entity (XXE)", category: "injection",
+		severity: finding.SeverityCritical, scope: ScopeForm, run: formCheck("xxe", TestXXE),
+	})
+	Register(&checkFunc{
+		id: "traversal", name: "Path Traversal", category: "file",
+		severity: finding.SeverityCritical, scope: ScopeURL, run: urlCheck("traversal", TestDirectoryTraversal),
+	})
+	Register(&checkFunc{
+		id: "lfi", name: "Local File Inclusion (LFI)", category: "file",
+		severity: finding.SeverityCritical, scope: ScopeURL, run: urlCheck("lfi", TestLFI),
+	})
+	Register(&checkFunc{
+		id: "ssrf", name: "Server-Side Request Forgery (SSRF)", category: "request-forgery",
+		severity: finding.SeverityCritical, scope: ScopeForm, run: formCheck("ssrf", TestSSRF),
+	})
+	Register(&checkFunc{
+		id: "csrf", name: "Formulário sem token CSRF", category: "request-forgery",
+		severity: finding.SeverityMedium, scope: ScopeForm, run: runCSRFCheck,
+	})
+	Register(&checkFunc{
+		id: "redirect", name: "Open Redirect", category: "redirect",
+		severity: finding.SeverityMedium, scope: ScopeForm, run: formCheck("redirect", TestOpenRedirect),
+	})
+	Register(&checkFunc{
+		id: "headers", name: "Headers de segurança", category: "config",
+		severity: finding.SeverityMedium, scope: ScopeHost, run: runHeadersCheck,
+	})
+	Register(&checkFunc{
+		id: "cookies", name: "Cookies inseguros", category: "config",
+		severity: finding.SeverityLow, scope: ScopeHost, run: runCookiesCheck,
+	})
+}
+
+// formCheck adapta um teste avançado por formulário
+func formCheck(id string, test func(context.Context, Form, string, *http.Client) []AdvancedVulnResult) func(context.Context, Target, *http.Client) []finding.Finding {
+	return func(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+		return FindingsFromAdvanced(id, target, test(ctx, target.Form, target.URL, client))
+	}
+}
+
+// urlCheck adapta um teste avançado por URL
+func urlCheck(id string, test func(context.Context, string, *http.Client) []AdvancedVulnResult) func(context.Context, Target, *http.Client) []finding.Finding {
+	return func(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+		return FindingsFromAdvanced(id, target, test(ctx, target.URL, client))
+	}
+}
+
+func runXSSCheck(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+	var findings []finding.Finding
+	for _, r := range testXSSField(ctx, target.Form, target.URL, target.Parameter, client) {
+		if r.Vulnerable {
+			findings = append(findings, XSSFinding(target.Form, target.URL, r))
+		}
+	}
+	return findings
+}
+
+func runSQLiCheck(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+	var findings []finding.Finding
+	for _, r := range TestSQLiDetailed(ctx, target.Form, target.URL, client) {
+		if r.Vulnerable {
+			findings = append(findings, SQLiFinding(target.Form, target.URL, r))
+		}
+	}
+	return findings
+}
+
+// runCSRFCheck reporta formulários que mudam estado sem token anti-CSRF.
+// Formulários GET ficam de fora: buscas e filtros não precisam de token.
+func runCSRFCheck(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+	if formMethod(target.Form) == http.MethodGet || HasCSRFToken(target.Form) {
+		return nil
+	}
+	return []finding.Finding{CSRFFinding(target.URL, target.Form)}
+}
+
+func runHeadersCheck(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+	var findings []finding.Finding
+	for _, h := range CheckSecurityHeaders(ctx, target.URL, client) {
+		// Headers presentes só são problema quando expõem informações
+		disclosure := h.Name == "Server" || h.Name == "X-Powered-By"
+		if h.Name == "Error" || (h.Present && !disclosure) {
+			continue
+		}
+		findings = append(findings, HeaderFinding(target.URL, h))
+	}
+	return findings
+}
+
+func runCookiesCheck(ctx context.Context, target Target, client *http.Client) []finding.Finding {
+	issues, err := AnalyzeCookies(ctx, target.URL, client)
+	if err != nil {
+		return nil
+	}
+
+	var findings []finding.Finding
+	for _, issue := range issues {
+		if len(issue.Missing) > 0 {
+			findings = append(findings, CookieFinding(target.URL, issue))
+		}
+	}
+	return findings
+}
@@ -1,31 +1,45 @@
 package scanner
 
 import (
-	"fmt"
+	"context"
 	"net/http"
+	"strings"
 )
 
-func CheckCookieSecurity(url string, client *http.Client) {
-	fmt.Println("\nVerificando cookies:")
+// CookieIssue lista os atributos de segurança ausentes em um cookie
+type CookieIssue struct {
+	Name    string
+	Missing []string // Secure, HttpOnly, SameSite
+}
 
-	resp, err := client.Get(url)
+// AnalyzeCookies retorna os cookies definidos pela página e os atributos de
+// segurança que faltam em cada um
+func AnalyzeCookies(ctx context.Context, url string, client *http.Client) ([]CookieIssue, error) {
+	resp, err := httpGet(ctx, client, url)
 	if err != nil {
-		fmt.Println("Erro ao acessar página:", err)
-		return
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	var issues []CookieIssue
 	for _, cookie := range resp.Cookies() {
-		fmt.Printf("- %s: ", cookie.Name)
+		issue := CookieIssue{Name: cookie.Name}
 		if !cookie.Secure {
-			fmt.Print("falta Secure, ")
+			issue.Missing = append(issue.Missing, "Secure")
 		}
 		if !cookie.HttpOnly {
-			fmt.Print("falta HttpOnly, ")
+			issue.Missing = append(issue.Missing, "HttpOnly")
 		}
-		if cookie.SameSite == http.SameSiteDefaultMode {
-			fmt.Print("falta SameSite")
+		// Sem o atributo o valor fica zerado, não SameSiteDefaultMode
+		if cookie.SameSite == 0 || cookie.SameSite == http.SameSiteDefaultMode {
+			issue.Missing = append(issue.Missing, "SameSite")
 		}
-		fmt.Println()
+		issues = append(issues, issue)
 	}
+	return issues, nil
+}
+
+// cookieSummary descreve os atributos ausentes, ex.: "falta Secure, HttpOnly"
+func cookieSummary(issue CookieIssue) string {
+	return "falta " + strings.Join(issue.Missing, ", ")
 }
@@ -1,9 +1,6 @@
 package scanner
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 var csrfFieldNames = []string{
 	"csrf_token",
@@ -12,24 +9,14 @@ var csrfFieldNames = []string{
 	"csrfmiddlewaretoken",
 }
 
-func CheckCSRFProtection(forms []Form) {
-	fmt.Println("\nVerificando proteção contra CSRF:")
-
-	for i, form := range forms {
-		hasToken := false
-		for _, input := range form.Inputs {
-			for _, tokenName := range csrfFieldNames {
-				if strings.ToLower(input) == strings.ToLower(tokenName) {
-					hasToken = true
-					break
-				}
+// HasCSRFToken informa se o formulário tem um campo de token anti-CSRF
+func HasCSRFToken(form Form) bool {
+	for _, input := range form.Inputs {
+		for _, tokenName := range csrfFieldNames {
+			if strings.EqualFold(input.Name, tokenName) {
+				return true
 			}
 		}
-
-		if hasToken {
-			fmt.Printf("Formulário %d tem proteção CSRF.\n", i+1)
-		} else {
-			fmt.Printf("Formulário %d NÃO tem proteção CSRF.\n", i+1)
-		}
 	}
+	return false
 }
@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DBMS identifica o banco de dados por trás de uma injeção
+type DBMS string
+
+const (
+	DBMSUnknown    DBMS = ""
+	DBMSMySQL      DBMS = "MySQL"
+	DBMSPostgreSQL DBMS = "PostgreSQL"
+	DBMSMSSQL      DBMS = "Microsoft SQL Server"
+	DBMSOracle     DBMS = "Oracle"
+	DBMSSQLite     DBMS = "SQLite"
+)
+
+// matchSQLError procura no corpo da resposta as assinaturas de erro de banco
+// da biblioteca e retorna o banco identificado, se houver, e a mensagem
+// encontrada. Assinaturas de um banco específico têm precedência sobre as
+// genéricas.
+func matchSQLError(body string) (DBMS, string, bool) {
+	for _, sig := range library.SignaturesFor(CategorySQLError) {
+		if indicator, found := sig.Match(body); found {
+			return sig.DBMS, indicator, true
+		}
+	}
+	return DBMSUnknown, "", false
+}
+
+// dbmsProbeGroup reúne as sondas de um banco
+type dbmsProbeGroup struct {
+	DBMS   DBMS
+	Probes []string
+}
+
+// dbmsProbes agrupa por banco os modelos dbms da biblioteca: condições
+// verdadeiras apenas no banco indicado (funções de versão, concatenação e
+// comentários específicos), que nos demais geram erro ou são falsas. Os
+// bancos ficam na ordem da biblioteca.
+func dbmsProbes() []dbmsProbeGroup {
+	var groups []dbmsProbeGroup
+	index := map[DBMS]int{}
+	for _, p := range techniquePayloads(SQLiTypeDBMS, DBMSUnknown) {
+		i, ok := index[p.DBMS]
+		if !ok {
+			i = len(groups)
+			index[p.DBMS] = i
+			groups = append(groups, dbmsProbeGroup{DBMS: p.DBMS})
+		}
+		groups[i].Probes = append(groups[i].Probes, p.Payload)
+	}
+	return groups
+}
+
+// confirmDBMS usa o campo injetável como oráculo booleano: compara a resposta
+// das sondas de cada banco com as de uma condição verdadeira e uma falsa,
+// montadas a partir dos modelos boolean da biblioteca. O candidato (ex.: vindo
+// da mensagem de erro) é testado primeiro. Só as sondas permitidas no nível de
+// segurança atual são enviadas. Retorna DBMSUnknown quando nenhum banco é
+// confirmado.
+func confirmDBMS(ctx context.Context, form Form, baseURL string, input Input, candidate DBMS, client *http.Client) DBMS {
+	prefix := baselineValue(input)
+
+	oracle, ok := booleanOracle(ctx, form, baseURL, input, client)
+	if !ok {
+		return DBMSUnknown
+	}
+	threshold := (1 + oracle.falseSimilarity) / 2
+
+	groups := dbmsProbes()
+	order := make([]int, 0, len(groups))
+	for i, group := range groups {
+		if group.DBMS == candidate {
+			order = append([]int{i}, order...)
+		} else {
+			order = append(order, i)
+		}
+	}
+
+	for _, i := range order {
+		group := groups[i]
+		confirmed := true
+		for _, probe := range group.Probes {
+			resp, err := fetchField(ctx, form, baseURL, input.Name, prefix+probe, client)
+			if err != nil {
+				confirmed = false
+				break
+			}
+			fp := fingerprint(resp, oracle.reference, prefix+probe)
+			if fp.Status != oracle.status || fp.Similarity < threshold {
+				confirmed = false
+				break
+			}
+		}
+		if confirmed {
+			return group.DBMS
+		}
+	}
+
+	return DBMSUnknown
+}
+
+// dbmsOracle é a referência das sondas: a resposta da condição verdadeira e o
+// quanto a falsa se afasta dela
+type dbmsOracle struct {
+	status          int
+	reference       string  // Corpo normalizado da condição verdadeira
+	falseSimilarity float64 // Similaridade da condição falsa com a verdadeira
+}
+
+// booleanOracle procura, entre os modelos boolean da biblioteca, um par de
+// condições cujas respostas se distinguem no campo
+func booleanOracle(ctx context.Context, form Form, baseURL string, input Input, client *http.Client) (dbmsOracle, bool) {
+	prefix := baselineValue(input)
+
+	for _, pair := range techniquePayloads(SQLiTypeBoolean, DBMSUnknown) {
+		if ctx.Err() != nil {
+			break
+		}
+		truePayload := prefix + fmt.Sprintf(pair.Payload, 1, 1)
+		falsePayload := prefix + fmt.Sprintf(pair.Payload, 1, 2)
+
+		trueResp, err := fetchField(ctx, form, baseURL, input.Name, truePayload, client)
+		if err != nil {
+			continue
+		}
+		falseResp, err := fetchField(ctx, form, baseURL, input.Name, falsePayload, client)
+		if err != nil {
+			continue
+		}
+
+		reference := normalizeBody(trueResp.body, truePayload)
+		falseFP := fingerprint(falseResp, reference, falsePayload)
+
+		// Sem diferença entre verdadeiro e falso não há oráculo para as sondas
+		if falseFP.Status == trueResp.status && falseFP.Similarity >= 1-booleanMargin {
+			continue
+		}
+		return dbmsOracle{status: trueResp.status, reference: reference, falseSimilarity: falseFP.Similarity}, true
+	}
+	return dbmsOracle{}, false
+}
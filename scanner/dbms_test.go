@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchSQLError(t *testing.T) {
+	tests := []struct {
+		body      string
+		wantDBMS  DBMS
+		wantFound bool
+	}{
+		{"You have an error in your SQL syntax; check the manual that corresponds to your MySQL server", DBMSMySQL, true},
+		{"ERROR: unterminated quoted string at or near \"'\"", DBMSPostgreSQL, true},
+		{"Unclosed quotation mark after the character string ''.", DBMSMSSQL, true},
+		{"ORA-01756: quoted string not properly terminated", DBMSOracle, true},
+		{"SQLiteException: near \"'\": syntax error", DBMSSQLite, true},
+		{"SQLSTATE[42000]: Syntax error or access violation", DBMSUnknown, true},
+		{"<p>Nenhum resultado</p>", DBMSUnknown, false},
+	}
+
+	for _, tt := range tests {
+		dbms, indicator, found := matchSQLError(tt.body)
+		if dbms != tt.wantDBMS || found != tt.wantFound {
+			t.Errorf("matchSQLError(%q) = %q, %q, %v, want %q, %v",
+				tt.body, dbms, indicator, found, tt.wantDBMS, tt.wantFound)
+		}
+	}
+}
+
+func TestConfirmDBMS(t *testing.T) {
+	// Simula uma busca em PostgreSQL: condições verdadeiras mantêm a lista,
+	// falsas a esvaziam e sintaxe de outros bancos gera erro
+	postgres := func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		switch q {
+		case "test", "test' AND '1'='1",
+			"test' AND current_setting('server_version') IS NOT NULL-- ",
+			"test' AND 'fd'||'c'='fdc'::text-- ":
+			fmt.Fprint(w, "<h1>Produtos</h1>"+productList)
+		case "test' AND '1'='2":
+			fmt.Fprint(w, "<h1>Produtos</h1><p>Nenhum produto encontrado</p>")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<p>Erro interno</p>")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		candidate DBMS
+		want      DBMS
+	}{
+		{"Candidato correto", postgres, DBMSPostgreSQL, DBMSPostgreSQL},
+		{"Candidato errado é corrigido", postgres, DBMSMySQL, DBMSPostgreSQL},
+		{"Sem candidato", postgres, DBMSUnknown, DBMSPostgreSQL},
+		{
+			name: "Sem oráculo booleano",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "<h1>Produtos</h1>"+productList)
+			},
+			candidate: DBMSMySQL,
+			want:      DBMSUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+			got := confirmDBMS(context.Background(), form, server.URL+"/busca", form.Inputs[0], tt.candidate, server.Client())
+			if got != tt.want {
+				t.Errorf("confirmDBMS(context.Background(), ) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmDBMSSafety(t *testing.T) {
+	defer SetSafetyLevel(CurrentSafetyLevel())
+
+	// Oracle: a view de sistema só é consultada no nível intrusive
+	var sentView bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if strings.Contains(q, "v$version") {
+			sentView = true
+		}
+		switch q {
+		case "test", "test' AND '1'='1",
+			"test' AND (SELECT banner FROM v$version WHERE ROWNUM=1) IS NOT NULL-- ",
+			"test' AND (SELECT 'fd'||'c' FROM DUAL)='fdc'-- ":
+			fmt.Fprint(w, "<h1>Produtos</h1>"+productList)
+		case "test' AND '1'='2":
+			fmt.Fprint(w, "<h1>Produtos</h1><p>Nenhum produto encontrado</p>")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<p>Erro interno</p>")
+		}
+	}))
+	defer server.Close()
+
+	form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+	for _, level := range []SafetyLevel{SafetySafe, SafetyIntrusive} {
+		SetSafetyLevel(level)
+		sentView = false
+		got := confirmDBMS(context.Background(), form, server.URL+"/busca", form.Inputs[0], DBMSOracle, server.Client())
+		if got != DBMSOracle {
+			t.Errorf("nível %s: confirmDBMS = %q, want %q", level, got, DBMSOracle)
+		}
+		if want := level >= SafetyIntrusive; sentView != want {
+			t.Errorf("nível %s: sonda da view de sistema enviada = %v, want %v", level, sentView, want)
+		}
+	}
+}
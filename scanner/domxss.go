@@ -0,0 +1,297 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+
+	"furador-de-coco/logger"
+)
+
+// Vetores de entrada testados no navegador
+const (
+	DOMVectorFragment = "fragment"
+	DOMVectorQuery    = "query"
+	DOMVectorForm     = "form"
+)
+
+// domSinkBinding é a função exposta pelo chromedp na página. Os hooks de
+// alert/prompt/confirm a chamam, e payloads podem chamá-la diretamente.
+const domSinkBinding = "furadorSink"
+
+// domHookScript substitui os diálogos antes de qualquer script da página
+var domHookScript = `(function () {
+	var report = function (kind, args) {
+		try { window.` + domSinkBinding + `(JSON.stringify({kind: kind, msg: String(args[0])})); } catch (e) {}
+	};
+	window.alert = function () { report("alert", arguments); };
+	window.prompt = function () { report("prompt", arguments); return null; };
+	window.confirm = function () { report("confirm", arguments); return false; };
+})();`
+
+// domFillScript preenche um campo, dispara os eventos que handlers de DOM
+// costumam escutar e envia o formulário do campo
+const domFillScript = `(function (name, value) {
+	var els = document.getElementsByName(name);
+	if (!els.length) return false;
+	var el = els[0];
+	el.value = value;
+	["input", "change", "keyup"].forEach(function (t) {
+		el.dispatchEvent(new Event(t, {bubbles: true}));
+	});
+	var f = el.form;
+	if (f) {
+		setTimeout(function () { f.requestSubmit ? f.requestSubmit() : f.submit(); }, 300);
+	}
+	return true;
+})(%s, %s)`
+
+// Payloads de DOM XSS. {{t}} é substituído por um token único, que liga a
+// execução observada ao payload que a causou.
+var domXSSPayloads = []string{
+	`<img src=x onerror=alert('{{t}}')>`,
+	`"><svg onload=alert('{{t}}')>`,
+	`'-alert('{{t}}')-'`,
+	`javascript:alert('{{t}}')`,
+}
+
+// domSettle é o tempo de espera por execução após carregar a página
+const domSettle = 1500 * time.Millisecond
+
+// DOMXSSResult representa um payload que executou JavaScript no navegador
+type DOMXSSResult struct {
+	URL     string // Página carregada
+	Vector  string // fragment, query ou form
+	Field   string // Parâmetro ou campo, quando aplicável
+	Payload string
+	Sink    string // alert, prompt, confirm ou sink
+}
+
+// domTest é uma carga de página com payload
+type domTest struct {
+	url     string
+	vector  string
+	field   string
+	payload string
+	token   string
+}
+
+// TestDOMXSS carrega as páginas no navegador headless com payloads no
+// fragmento, na query e nos campos dos formulários. Um resultado só é gerado
+// quando o JavaScript do payload de fato executa.
+func TestDOMXSS(ctx context.Context, pages []string, forms []Form, timeout time.Duration) ([]DOMXSSResult, error) {
+	browserCtx, cancel := chromedp.NewContext(ctx)
+	defer cancel()
+
+	// Inicia o navegador antes dos testes para reportar ausência do Chrome
+	if err := chromedp.Run(browserCtx); err != nil {
+		return nil, fmt.Errorf("erro ao iniciar navegador headless: %w", err)
+	}
+
+	var tests []domTest
+	for _, pageURL := range pages {
+		tests = append(tests, domURLTests(pageURL)...)
+	}
+	for _, form := range forms {
+		tests = append(tests, domFormTests(form)...)
+	}
+
+	var results []DOMXSSResult
+	found := make(map[string]bool) // vetor+campo já confirmado
+
+	for _, test := range tests {
+		if ctx.Err() != nil {
+			break
+		}
+		key := test.url + "|" + test.vector + "|" + test.field
+		if found[key] {
+			continue
+		}
+
+		sink, err := runDOMTest(browserCtx, test, timeout)
+		if err != nil {
+			logger.Debug("Erro no teste de DOM XSS em %s: %v", test.url, err)
+			continue
+		}
+		if sink == "" {
+			continue
+		}
+
+		found[key] = true
+		results = append(results, DOMXSSResult{
+			URL:     test.url,
+			Vector:  test.vector,
+			Field:   test.field,
+			Payload: test.payload,
+			Sink:    sink,
+		})
+	}
+
+	return results, nil
+}
+
+// domURLTests gera testes com payload no fragmento e em cada parâmetro da query
+func domURLTests(pageURL string) []domTest {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	u.Fragment = ""
+	base := u.String()
+	query := u.Query()
+
+	var tests []domTest
+	for _, tmpl := range domXSSPayloads {
+		token := newCanary()
+		payload := strings.ReplaceAll(tmpl, "{{t}}", token)
+
+		tests = append(tests, domTest{
+			url:     base + "#" + payload,
+			vector:  DOMVectorFragment,
+			payload: payload,
+			token:   token,
+		})
+
+		for _, name := range sortedKeys(query) {
+			token := newCanary()
+			payload := strings.ReplaceAll(tmpl, "{{t}}", token)
+
+			q := url.Values{}
+			for k, v := range query {
+				q[k] = v
+			}
+			q.Set(name, payload)
+
+			withPayload := *u
+			withPayload.RawQuery = q.Encode()
+			tests = append(tests, domTest{
+				url:     withPayload.String(),
+				vector:  DOMVectorQuery,
+				field:   name,
+				payload: payload,
+				token:   token,
+			})
+		}
+	}
+	return tests
+}
+
+// domFormTests gera testes que preenchem cada campo do formulário na página
+// onde ele foi encontrado
+func domFormTests(form Form) []domTest {
+	if form.Page == "" {
+		return nil
+	}
+
+	var tests []domTest
+	for _, input := range form.Inputs {
+		if input.Name == "" || input.Tag == "json" || input.Type == "submit" || input.Type == "hidden" {
+			continue
+		}
+		for _, tmpl := range domXSSPayloads {
+			token := newCanary()
+			tests = append(tests, domTest{
+				url:     form.Page,
+				vector:  DOMVectorForm,
+				field:   input.Name,
+				payload: strings.ReplaceAll(tmpl, "{{t}}", token),
+				token:   token,
+			})
+		}
+	}
+	return tests
+}
+
+// runDOMTest carrega a página em uma nova aba e retorna o sink que executou
+// o token do teste, ou "" se nada executou
+func runDOMTest(browserCtx context.Context, test domTest, timeout time.Duration) (string, error) {
+	tabCtx, cancel := chromedp.NewContext(browserCtx)
+	defer cancel()
+	tabCtx, cancelTimeout := context.WithTimeout(tabCtx, timeout)
+	defer cancelTimeout()
+
+	var mu sync.Mutex
+	sink := ""
+	record := func(kind, msg string) {
+		if !strings.Contains(msg, test.token) {
+			return
+		}
+		mu.Lock()
+		if sink == "" {
+			sink = kind
+		}
+		mu.Unlock()
+	}
+
+	chromedp.ListenTarget(tabCtx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *runtime.EventBindingCalled:
+			if ev.Name != domSinkBinding {
+				return
+			}
+			var call struct{ Kind, Msg string }
+			if err := json.Unmarshal([]byte(ev.Payload), &call); err != nil || call.Kind == "" {
+				// Chamada direta do sink pelo payload
+				record("sink", ev.Payload)
+				return
+			}
+			record(call.Kind, call.Msg)
+
+		case *page.EventJavascriptDialogOpening:
+			// Diálogos que escaparam do hook (ex.: iframes) também contam
+			record(string(ev.Type), ev.Message)
+			go chromedp.Run(tabCtx, page.HandleJavaScriptDialog(false))
+		}
+	})
+
+	actions := []chromedp.Action{
+		runtime.AddBinding(domSinkBinding),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := page.AddScriptToEvaluateOnNewDocument(domHookScript).Do(ctx)
+			return err
+		}),
+		chromedp.Navigate(test.url),
+	}
+
+	if test.vector == DOMVectorForm {
+		name, _ := json.Marshal(test.field)
+		value, _ := json.Marshal(test.payload)
+		var filled bool
+		actions = append(actions,
+			chromedp.Evaluate(fmt.Sprintf(domFillScript, name, value), &filled))
+	}
+	actions = append(actions, chromedp.Sleep(domSettle))
+
+	if err := chromedp.Run(tabCtx, actions...); err != nil {
+		// Execução observada antes do erro (ex.: navegação do submit) é válida
+		mu.Lock()
+		defer mu.Unlock()
+		if sink != "" {
+			return sink, nil
+		}
+		return "", err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return sink, nil
+}
+
+// sortedKeys retorna as chaves da query em ordem alfabética
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDOMURLTests(t *testing.T) {
+	tests := domURLTests("https://example.com/busca?q=a&page=2#antigo")
+
+	counts := make(map[string]int)
+	for _, test := range tests {
+		counts[test.vector+":"+test.field]++
+
+		if !strings.Contains(test.payload, test.token) {
+			t.Errorf("payload %q sem o token %q", test.payload, test.token)
+		}
+		if strings.Contains(test.url, "antigo") {
+			t.Errorf("fragmento original mantido em %q", test.url)
+		}
+
+		switch test.vector {
+		case DOMVectorFragment:
+			if !strings.HasSuffix(test.url, "#"+test.payload) {
+				t.Errorf("payload fora do fragmento: %q", test.url)
+			}
+		case DOMVectorQuery:
+			u, err := url.Parse(test.url)
+			if err != nil {
+				t.Fatalf("URL inválida %q: %v", test.url, err)
+			}
+			if got := u.Query().Get(test.field); got != test.payload {
+				t.Errorf("parâmetro %s = %q, want %q", test.field, got, test.payload)
+			}
+		}
+	}
+
+	n := len(domXSSPayloads)
+	want := map[string]int{"fragment:": n, "query:page": n, "query:q": n}
+	for key, count := range want {
+		if counts[key] != count {
+			t.Errorf("%s: %d testes, want %d", key, counts[key], count)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("vetores inesperados: %v", counts)
+	}
+}
+
+func TestDOMFormTests(t *testing.T) {
+	form := Form{
+		Page: "https://example.com/contato",
+		Inputs: []Input{
+			{Name: "nome", Type: "text"},
+			{Name: "csrf", Type: "hidden"},
+			{Name: "enviar", Tag: "button", Type: "submit"},
+		},
+	}
+
+	tests := domFormTests(form)
+	if len(tests) != len(domXSSPayloads) {
+		t.Fatalf("%d testes, want %d", len(tests), len(domXSSPayloads))
+	}
+	for _, test := range tests {
+		if test.field != "nome" || test.url != form.Page || test.vector != DOMVectorForm {
+			t.Errorf("teste inesperado: %+v", test)
+		}
+	}
+
+	if got := domFormTests(Form{Inputs: form.Inputs}); got != nil {
+		t.Errorf("formulário sem página gerou testes: %+v", got)
+	}
+}
@@ -7,8 +7,8 @@ import (
 	"github.com/chromedp/chromedp"
 )
 
-func GetRenderedHTML(url string) (string, error) {
-	ctx, cancel := chromedp.NewContext(context.Background())
+func GetRenderedHTML(ctx context.Context, url string) (string, error) {
+	ctx, cancel := chromedp.NewContext(ctx)
 	defer cancel()
 
 	var html string
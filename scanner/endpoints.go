@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// APIEndpoint descreve um endpoint de API (ex.: SPAs que esperam JSON) que
+// não aparece como <form> no HTML
+type APIEndpoint struct {
+	URL      string                 `json:"url"`
+	Method   string                 `json:"method"`
+	Encoding string                 `json:"encoding"` // Padrão: application/json
+	Headers  map[string]string      `json:"headers"`
+	Params   map[string]interface{} `json:"params"` // Nome do campo -> valor padrão
+}
+
+// LoadAPIEndpoints carrega definições de endpoints de um arquivo JSON e as
+// converte em formulários que passam pelos mesmos testes de injeção
+func LoadAPIEndpoints(path string) ([]Form, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []APIEndpoint
+	if err := json.Unmarshal(data, &endpoints); err != nil {
+		return nil, fmt.Errorf("arquivo de endpoints inválido: %w", err)
+	}
+
+	var forms []Form
+	for i, ep := range endpoints {
+		form, err := ep.Form()
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %d: %w", i+1, err)
+		}
+		forms = append(forms, form)
+	}
+	return forms, nil
+}
+
+// Form converte o endpoint em um formulário
+func (ep APIEndpoint) Form() (Form, error) {
+	if ep.URL == "" {
+		return Form{}, fmt.Errorf("url é obrigatória")
+	}
+
+	form := Form{
+		Action:  ep.URL,
+		Method:  strings.ToUpper(ep.Method),
+		Enctype: strings.ToLower(ep.Encoding),
+		Page:    ep.URL,
+		Headers: ep.Headers,
+	}
+	if form.Method == "" {
+		form.Method = "POST"
+	}
+	if form.Enctype == "" {
+		form.Enctype = EncodingJSON
+	}
+
+	names := make([]string, 0, len(ep.Params))
+	for name := range ep.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		input := Input{Name: name, Tag: "json", Type: "text"}
+		switch v := ep.Params[name].(type) {
+		case float64:
+			input.Type = "number"
+			input.Value = fmt.Sprint(v)
+		case bool:
+			input.Type = "boolean"
+			input.Value = fmt.Sprint(v)
+		case string:
+			input.Value = v
+		case nil:
+		default:
+			return Form{}, fmt.Errorf("parâmetro %q: apenas valores string, número ou booleano são suportados", name)
+		}
+		form.Inputs = append(form.Inputs, input)
+	}
+
+	return form, nil
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"furador-de-coco/finding"
+)
+
+type failuresKey struct{}
+
+// RequestFailures conta as requisições que falharam durante a execução de
+// uma verificação. Uma verificação com falhas não pode afirmar que o alvo é
+// seguro.
+type RequestFailures struct {
+	mu    sync.Mutex
+	count int
+	last  error
+}
+
+// TrackFailures retorna um contexto em que as requisições do scanner que
+// falharem são registradas no RequestFailures retornado
+func TrackFailures(ctx context.Context) (context.Context, *RequestFailures) {
+	failures := &RequestFailures{}
+	return context.WithValue(ctx, failuresKey{}, failures), failures
+}
+
+// recordFailure registra a falha de uma requisição no RequestFailures do
+// contexto. Cancelamento do scan não conta: o relatório já sai incompleto.
+func recordFailure(ctx context.Context, err error) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	failures, ok := ctx.Value(failuresKey{}).(*RequestFailures)
+	if !ok {
+		return
+	}
+	failures.mu.Lock()
+	failures.count++
+	failures.last = err
+	failures.mu.Unlock()
+}
+
+// recordPanic registra o panic de uma verificação como falha, para o alvo
+// sair inconclusivo em vez de derrubar o worker
+func (f *RequestFailures) recordPanic(v any) {
+	f.mu.Lock()
+	f.count++
+	f.last = fmt.Errorf("panic: %v", v)
+	f.mu.Unlock()
+}
+
+// CheckError retorna a falha da verificação no alvo, ou nil se nenhuma
+// requisição falhou
+func (f *RequestFailures) CheckError(checkID, url, parameter string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.count == 0 {
+		return nil
+	}
+	return &CheckError{
+		CheckID:   checkID,
+		URL:       url,
+		Parameter: parameter,
+		Failures:  f.count,
+		Err:       f.last,
+	}
+}
+
+// Inconclusive retorna o registro de verificação inconclusiva do alvo, ou
+// nil se nenhuma requisição falhou
+func (f *RequestFailures) Inconclusive(checkID, url, parameter string) *finding.Inconclusive {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.count == 0 {
+		return nil
+	}
+	return &finding.Inconclusive{
+		CheckID:   checkID,
+		URL:       url,
+		Parameter: parameter,
+		Failures:  f.count,
+		Reason:    f.last.Error(),
+	}
+}
+
+// CheckError é a falha de uma verificação em um alvo: requisições que
+// falharam mesmo após as novas tentativas, ou panic na execução
+type CheckError struct {
+	CheckID   string
+	URL       string
+	Parameter string
+	Failures  int
+	Err       error // Última falha
+}
+
+func (e *CheckError) Error() string {
+	target := e.URL
+	if e.Parameter != "" {
+		target += " (" + e.Parameter + ")"
+	}
+	return fmt.Sprintf("verificação %s em %s: %d falha(s): %v", e.CheckID, target, e.Failures, e.Err)
+}
+
+func (e *CheckError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,269 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"furador-de-coco/finding"
+)
+
+// findingInfo são os dados fixos de cada tipo de finding
+type findingInfo struct {
+	Title       string
+	Severity    finding.Severity
+	CWE         string
+	OWASP       string
+	Remediation string
+}
+
+const (
+	owaspAccessControl = "A01:2021-Broken Access Control"
+	owaspInjection     = "A03:2021-Injection"
+	owaspMisconfig     = "A05:2021-Security Misconfiguration"
+	owaspSSRF          = "A10:2021-Server-Side Request Forgery"
+)
+
+var findingCatalog = map[string]findingInfo{
+	"xss": {
+		Title: "Cross-Site Scripting (XSS)", Severity: finding.SeverityHigh, CWE: "CWE-79", OWASP: owaspInjection,
+		Remediation: "Codifique a saída conforme o contexto (HTML, atributo, JavaScript, URL) e adote Content-Security-Policy.",
+	},
+	"stored-xss": {
+		Title: "XSS armazenado", Severity: finding.SeverityHigh, CWE: "CWE-79", OWASP: owaspInjection,
+		Remediation: "Codifique os dados armazenados na exibição, conforme o contexto, e adote Content-Security-Policy.",
+	},
+	"dom-xss": {
+		Title: "DOM XSS", Severity: finding.SeverityHigh, CWE: "CWE-79", OWASP: owaspInjection,
+		Remediation: "Não passe dados de location, parâmetros ou campos para sinks como innerHTML, document.write ou eval; use textContent.",
+	},
+	"sqli": {
+		Title: "SQL Injection", Severity: finding.SeverityCritical, CWE: "CWE-89", OWASP: owaspInjection,
+		Remediation: "Use consultas parametrizadas (prepared statements) e nunca concatene entrada do usuário em SQL.",
+	},
+	"cmdi": {
+		Title: "Command Injection", Severity: finding.SeverityCritical, CWE: "CWE-78", OWASP: owaspInjection,
+		Remediation: "Não execute comandos do shell com entrada do usuário; use APIs com argumentos separados e listas de valores permitidos.",
+	},
+	"xxe": {
+		Title: "XML External Entity (XXE)", Severity: finding.SeverityCritical, CWE: "CWE-611", OWASP: owaspMisconfig,
+		Remediation: "Desabilite DTDs e a resolução de entidades externas no parser XML.",
+	},
+	"traversal": {
+		Title: "Path Traversal", Severity: finding.SeverityCritical, CWE: "CWE-22", OWASP: owaspAccessControl,
+		Remediation: "Normalize o caminho e restrinja o acesso a um diretório base; prefira identificadores a nomes de arquivo.",
+	},
+	"lfi": {
+		Title: "Local File Inclusion (LFI)", Severity: finding.SeverityCritical, CWE: "CWE-98", OWASP: owaspInjection,
+		Remediation: "Inclua apenas arquivos de uma lista fixa, escolhidos por identificador e nunca pelo caminho enviado.",
+	},
+	"ssrf": {
+		Title: "Server-Side Request Forgery (SSRF)", Severity: finding.SeverityCritical, CWE: "CWE-918", OWASP: owaspSSRF,
+		Remediation: "Valide as URLs contra uma lista de destinos permitidos e bloqueie endereços internos e de metadata.",
+	},
+	"csrf": {
+		Title: "Formulário sem token CSRF", Severity: finding.SeverityMedium, CWE: "CWE-352", OWASP: owaspAccessControl,
+		Remediation: "Inclua um token anti-CSRF por sessão nos formulários que alteram estado e use cookies SameSite.",
+	},
+	"redirect": {
+		Title: "Open Redirect", Severity: finding.SeverityMedium, CWE: "CWE-601", OWASP: owaspAccessControl,
+		Remediation: "Redirecione apenas para caminhos relativos ou destinos de uma lista permitida.",
+	},
+	"headers": {
+		Title: "Header de segurança", Severity: finding.SeverityMedium, CWE: "CWE-693", OWASP: owaspMisconfig,
+		Remediation: "Configure os headers de segurança no servidor ou no proxy reverso e remova headers que expõem tecnologia.",
+	},
+	"cookies": {
+		Title: "Cookie inseguro", Severity: finding.SeverityLow, CWE: "CWE-614", OWASP: owaspMisconfig,
+		Remediation: "Defina Secure, HttpOnly e SameSite nos cookies, principalmente nos de sessão.",
+	},
+}
+
+// newFinding cria um finding com os dados do catálogo do tipo
+func newFinding(checkID string) finding.Finding {
+	info := findingCatalog[checkID]
+	return finding.Finding{
+		CheckID:     checkID,
+		Title:       info.Title,
+		Severity:    info.Severity,
+		Confidence:  finding.ConfidenceFirm,
+		CWE:         info.CWE,
+		OWASP:       info.OWASP,
+		Remediation: info.Remediation,
+	}
+}
+
+// formMethod retorna o método do formulário em maiúsculas (GET por padrão)
+func formMethod(form Form) string {
+	if form.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(form.Method)
+}
+
+// requestExcerpt descreve a requisição que enviou o payload no campo
+func requestExcerpt(form Form, baseURL, field, payload string) string {
+	target, err := form.TargetURL(baseURL)
+	if err != nil {
+		return ""
+	}
+	req, err := buildRequest(form, target, buildTestData(form, field, payload))
+	if err != nil {
+		return ""
+	}
+
+	excerpt := req.Method + " " + req.URL.String()
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, 1000))
+			excerpt += "\n\n" + string(data)
+		}
+	}
+	return excerpt
+}
+
+// XSSFinding converte um XSS refletido confirmado
+func XSSFinding(form Form, baseURL string, r XSSResult) finding.Finding {
+	f := newFinding("xss")
+	f.Confidence = finding.ConfidenceConfirmed // Breakout verificado no DOM
+	f.URL = baseURL
+	f.Method = formMethod(form)
+	f.Parameter = r.Field
+	f.Payload = r.Payload
+	f.Evidence = "breakout confirmado no contexto " + r.Context
+	f.Description = r.Description
+	f.Request = requestExcerpt(form, baseURL, r.Field, r.Payload)
+	f.Response = r.Response
+	return f
+}
+
+// SQLiFinding converte uma injeção SQL detectada
+func SQLiFinding(form Form, baseURL string, r SQLiResult) finding.Finding {
+	f := newFinding("sqli")
+	f.Title = "SQL Injection (" + SQLiLabel(r.Type, r.DBMS) + ")"
+	if r.Type == "union" {
+		f.Confidence = finding.ConfidenceConfirmed // Marcador concatenado pelo banco
+	}
+	f.URL = baseURL
+	f.Method = formMethod(form)
+	f.Parameter = r.Field
+	f.Payload = r.Payload
+	f.Evidence = r.Indicator
+	f.Description = r.Description
+	f.Request = requestExcerpt(form, baseURL, r.Field, r.Payload)
+	f.Response = r.Response
+	return f
+}
+
+// StoredXSSFinding converte um XSS armazenado
+func StoredXSSFinding(r StoredXSSResult) finding.Finding {
+	f := newFinding("stored-xss")
+	f.Confidence = finding.ConfidenceConfirmed
+	f.URL = r.SubmitURL
+	f.Method = strings.ToUpper(r.FormMethod)
+	f.Parameter = r.Field
+	f.Payload = r.Payload
+	f.Evidence = "payload exibido em " + r.DisplayURL
+	f.Description = fmt.Sprintf("Valor enviado ao formulário %s %s é exibido sem codificação em %s", f.Method, r.FormAction, r.DisplayURL)
+	return f
+}
+
+// DOMXSSFinding converte um DOM XSS executado no navegador
+func DOMXSSFinding(r DOMXSSResult) finding.Finding {
+	f := newFinding("dom-xss")
+	f.Confidence = finding.ConfidenceConfirmed // JavaScript executado
+	f.URL = r.URL
+	f.Method = http.MethodGet
+	f.Parameter = r.Field
+	f.Payload = r.Payload
+	f.Evidence = "executado via " + r.Sink
+	f.Description = fmt.Sprintf("Payload via %s executou JavaScript no navegador", r.Vector)
+	return f
+}
+
+// AdvancedFinding converte um resultado dos testes avançados
+func AdvancedFinding(checkID string, target Target, r AdvancedVulnResult) finding.Finding {
+	f := newFinding(checkID)
+	f.Title = r.Type
+	f.Severity = finding.ParseSeverity(r.Severity)
+	if checkID == "ssrf" {
+		// A detecção também aceita respostas rápidas, o que é heurístico
+		f.Confidence = finding.ConfidenceTentative
+	}
+	f.URL = target.URL
+	f.Method = http.MethodGet
+	if target.Form.Action != "" || len(target.Form.Inputs) > 0 {
+		f.Method = formMethod(target.Form)
+	}
+	f.Parameter = r.Field
+	f.Payload = r.Payload
+	f.Evidence = r.Evidence
+	f.Description = r.Description
+	return f
+}
+
+// FindingsFromAdvanced converte os resultados vulneráveis dos testes
+// avançados
+func FindingsFromAdvanced(checkID string, target Target, results []AdvancedVulnResult) []finding.Finding {
+	var findings []finding.Finding
+	for _, r := range results {
+		if r.Vulnerable {
+			findings = append(findings, AdvancedFinding(checkID, target, r))
+		}
+	}
+	return findings
+}
+
+// HeaderFinding converte um header de segurança ausente ou que expõe
+// informações
+func HeaderFinding(pageURL string, h SecurityHeader) finding.Finding {
+	f := newFinding("headers")
+	f.Title = "Header " + h.Name
+	f.Severity = finding.ParseSeverity(h.Severity)
+	f.Confidence = finding.ConfidenceConfirmed
+	f.URL = pageURL
+	f.Method = http.MethodGet
+	f.Evidence = h.Value
+	f.Description = h.Message
+	return f
+}
+
+// CookieFinding converte um cookie sem atributos de segurança
+func CookieFinding(pageURL string, issue CookieIssue) finding.Finding {
+	f := newFinding("cookies")
+	f.Title = "Cookie " + issue.Name
+	f.Confidence = finding.ConfidenceConfirmed
+	f.URL = pageURL
+	f.Method = http.MethodGet
+	f.Parameter = issue.Name
+	f.Evidence = cookieSummary(issue)
+	f.Description = fmt.Sprintf("Cookie '%s' sem atributos de segurança", issue.Name)
+	return f
+}
+
+// CSRFFinding converte um formulário sem token anti-CSRF
+func CSRFFinding(pageURL string, form Form) finding.Finding {
+	f := newFinding("csrf")
+	// O token é procurado só pelos nomes mais comuns e pode vir em header ou
+	// cookie SameSite
+	f.Confidence = finding.ConfidenceTentative
+	f.URL = pageURL
+	f.Method = formMethod(form)
+	f.Description = fmt.Sprintf("Formulário %s %s não tem campo de token anti-CSRF", f.Method, form.Action)
+	if target, err := form.TargetURL(pageURL); err == nil {
+		f.Evidence = "campos: " + strings.Join(inputNames(form), ", ")
+		f.Request = f.Method + " " + target
+	}
+	return f
+}
+
+func inputNames(form Form) []string {
+	var names []string
+	for _, input := range form.Inputs {
+		if input.Name != "" {
+			names = append(names, input.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// Valores de baseline por tipo de campo, válidos para a validação do HTML5
+var baselineByType = map[string]string{
+	"email":          "test@example.com",
+	"url":            "https://example.com",
+	"tel":            "11999999999",
+	"number":         "1",
+	"range":          "1",
+	"date":           "2024-01-01",
+	"datetime-local": "2024-01-01T12:00",
+	"month":          "2024-01",
+	"week":           "2024-W01",
+	"time":           "12:00",
+	"color":          "#000000",
+	"password":       "Test1234!",
+}
+
+// Candidatos testados quando o campo tem um pattern que "test" não satisfaz
+var patternCandidates = []string{
+	"test", "1", "123456", "12345678", "test@example.com", "Test1234", "A1", "abc",
+}
+
+// buildTestData constrói os dados do formulário com o payload no campo alvo
+// e valores de baseline válidos nos demais
+func buildTestData(form Form, targetField string, payload string) url.Values {
+	data := url.Values{}
+	for _, input := range form.Inputs {
+		if input.Name == targetField {
+			data.Set(input.Name, payload)
+		} else {
+			data.Set(input.Name, baselineValue(input))
+		}
+	}
+	return data
+}
+
+// baselineValue retorna um valor válido para o campo, usado quando ele não
+// é o alvo do teste
+func baselineValue(input Input) string {
+	// Campos com valor padrão (hidden com tokens, selects, checkboxes marcados)
+	if input.Value != "" {
+		return input.Value
+	}
+
+	switch input.Type {
+	case "select", "radio", "checkbox":
+		for _, option := range input.Options {
+			if option != "" {
+				return option
+			}
+		}
+		return "on"
+	case "number", "range":
+		if input.Min != "" {
+			if _, err := strconv.ParseFloat(input.Min, 64); err == nil {
+				return input.Min
+			}
+		}
+	}
+
+	value := "test"
+	if v, ok := baselineByType[input.Type]; ok {
+		value = v
+	}
+
+	if input.Pattern != "" {
+		if re, err := regexp.Compile("^(?:" + input.Pattern + ")$"); err == nil && !re.MatchString(value) {
+			for _, candidate := range patternCandidates {
+				if re.MatchString(candidate) {
+					value = candidate
+					break
+				}
+			}
+		}
+	}
+
+	if input.MaxLength > 0 && len(value) > input.MaxLength {
+		value = value[:input.MaxLength]
+	}
+
+	return value
+}
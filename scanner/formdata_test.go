@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestBaselineValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Input
+		want  string
+	}{
+		{
+			name:  "Texto sem restrições",
+			input: Input{Name: "q", Tag: "input", Type: "text"},
+			want:  "test",
+		},
+		{
+			name:  "Hidden mantém o token",
+			input: Input{Name: "csrf", Tag: "input", Type: "hidden", Value: "abc123"},
+			want:  "abc123",
+		},
+		{
+			name:  "Email",
+			input: Input{Name: "email", Tag: "input", Type: "email"},
+			want:  "test@example.com",
+		},
+		{
+			name:  "Number usa min",
+			input: Input{Name: "qty", Tag: "input", Type: "number", Min: "5"},
+			want:  "5",
+		},
+		{
+			name:  "Select sem opção marcada usa a primeira não vazia",
+			input: Input{Name: "uf", Tag: "select", Type: "select", Options: []string{"", "SP", "RJ"}},
+			want:  "SP",
+		},
+		{
+			name:  "Pattern numérico",
+			input: Input{Name: "cep", Tag: "input", Type: "text", Pattern: `\d{8}`},
+			want:  "12345678",
+		},
+		{
+			name:  "Maxlength trunca o valor",
+			input: Input{Name: "code", Tag: "input", Type: "text", MaxLength: 2},
+			want:  "te",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := baselineValue(tt.input); got != tt.want {
+				t.Errorf("baselineValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTestData(t *testing.T) {
+	form := Form{Inputs: []Input{
+		{Name: "email", Tag: "input", Type: "email"},
+		{Name: "comment", Tag: "textarea", Type: "textarea"},
+		{Name: "token", Tag: "input", Type: "hidden", Value: "xyz"},
+	}}
+
+	data := buildTestData(form, "comment", "<payload>")
+
+	if got := data.Get("comment"); got != "<payload>" {
+		t.Errorf("comment = %q, want payload", got)
+	}
+	if got := data.Get("email"); got != "test@example.com" {
+		t.Errorf("email = %q, want test@example.com", got)
+	}
+	if got := data.Get("token"); got != "xyz" {
+		t.Errorf("token = %q, want xyz", got)
+	}
+}
@@ -0,0 +1,520 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Tipos das unidades de trabalho de um campo. A ordem é a da remontagem dos
+// resultados, a mesma dos testes sequenciais.
+type unitKind int
+
+const (
+	unitXSSProbe    unitKind = iota // Canary que descobre os contextos de reflexão
+	unitXSSBreakout                 // Um payload de breakout para um contexto
+	unitSQLiError                   // Um payload de erro
+	unitSQLiUnion                   // UNION com marcador
+	unitSQLiBoolean                 // Blind boolean
+	unitSQLiTime                    // Um payload blind time-based
+	unitSQLiDBMS                    // Sondas que confirmam o banco da injeção
+)
+
+// Estágios SQLi de um campo. Cada estágio só começa quando o anterior
+// termina; os estágios blind só rodam se nenhuma injeção foi encontrada.
+const (
+	sqliStageNone = iota
+	sqliStageError
+	sqliStageBlind // UNION e boolean
+	sqliStageTime
+	sqliStageDBMS
+	sqliStageDone
+)
+
+// fieldUnit é uma unidade de trabalho (formulário, campo, payload) executada
+// por um worker do pool. A saída é escrita pelo worker e lida pelo
+// coordenador depois que o resultado volta pelo canal.
+type fieldUnit struct {
+	kind  unitKind
+	form  *formScan
+	field *fieldScan
+	seq   int // Posição no estágio, para remontar na ordem sequencial
+
+	sqliPayload SQLiPayload
+	reflection  Reflection
+	breakout    contextPayload
+	timePayload PayloadDef
+	dbmsHint    DBMS
+
+	reflections []Reflection
+	xss         []XSSResult
+	sqli        []SQLiResult
+	dbms        DBMS
+	failures    *RequestFailures
+	duration    time.Duration
+}
+
+// fieldScan acompanha os testes de um campo
+type fieldScan struct {
+	index int
+	input Input
+
+	// Marcados pelo coordenador no primeiro resultado vulnerável; os workers
+	// pulam as unidades restantes do campo
+	xssFound  atomic.Bool
+	sqliFound atomic.Bool
+
+	sqliStage   int
+	sqliPending int // Unidades do estágio SQLi atual ainda não concluídas
+}
+
+// formScan acompanha as unidades de um formulário até a remontagem do
+// resultado
+type formScan struct {
+	job     ScanJob
+	fields  []*fieldScan
+	pending int
+	units   []*fieldUnit // Unidades concluídas
+	err     error        // Cancelamento antes de todas as unidades rodarem
+}
+
+// ScanForms testa XSS e SQLi (conforme tests, por ID) nos formulários dos
+// jobs, dividindo o trabalho em unidades (formulário, campo, payload)
+// distribuídas entre os workers do pool. Unidades que dependem de respostas
+// anteriores (payloads de breakout após o canary, técnicas blind após os
+// payloads de erro) são agendadas quando elas chegam. Cada formulário é
+// remontado na ordem dos testes sequenciais e enviado no canal assim que
+// todas as suas unidades terminam; o canal é fechado no fim.
+//
+// Diferente de TestSQLiDetailed, o banco encontrado em um campo não restringe
+// os payloads dos outros, que rodam ao mesmo tempo.
+//
+// Com o contexto cancelado as unidades pendentes são descartadas e os
+// formulários afetados saem com Error. Cada unidade concluída é salva no
+// journal do contexto (WithJournal): num scan retomado os formulários
+// concluídos não são testados de novo, e os interrompidos são remontados a
+// partir das unidades salvas, rodando só as que faltaram.
+func ScanForms(ctx context.Context, jobs []ScanJob, client *http.Client, workers int, tests map[string]bool) <-chan ScanJobResult {
+	results := make(chan ScanJobResult)
+
+	go func() {
+		defer close(results)
+
+		var queue []*fieldUnit
+		for _, job := range jobs {
+			if saved, ok := LookupJob(ctx, formKey(job)); ok {
+				saved.FormIndex, saved.Form = job.FormIndex, job.Form
+				results <- saved
+				continue
+			}
+
+			f := &formScan{job: job}
+			for i, input := range job.Form.Inputs {
+				f.fields = append(f.fields, &fieldScan{index: i, input: input})
+			}
+			units := f.initialUnits(tests)
+			if f.pending == 0 {
+				results <- f.result()
+				continue
+			}
+			queue = append(queue, f.resume(ctx, units, results)...)
+		}
+		if len(queue) == 0 {
+			return
+		}
+
+		pool := NewWorkerPool(workers)
+		pool.Start(ctx, client)
+
+		inFlight := 0
+		for len(queue) > 0 || inFlight > 0 {
+			var submit chan<- ScanJob
+			var next ScanJob
+			if len(queue) > 0 {
+				if ctx.Err() != nil {
+					// Cancelado: as unidades na fila são descartadas
+					for _, u := range queue {
+						u.form.complete(ctx, u, true, results)
+					}
+					queue = nil
+					continue
+				}
+				submit = pool.jobs
+				next = ScanJob{unit: queue[0]}
+			}
+
+			select {
+			case submit <- next:
+				queue = queue[1:]
+				inFlight++
+			case r := <-pool.results:
+				inFlight--
+				if r.Error == nil {
+					r.unit.record(ctx)
+				}
+				// As unidades seguintes vão para a frente da fila, para os
+				// formulários em andamento terminarem antes de outros começarem
+				queue = append(r.unit.form.complete(ctx, r.unit, r.Error != nil, results), queue...)
+			}
+		}
+
+		pool.Close()
+	}()
+
+	return results
+}
+
+// initialUnits cria as unidades que não dependem de nenhuma resposta: o
+// canary XSS e os payloads de erro SQLi de cada campo
+func (f *formScan) initialUnits(tests map[string]bool) []*fieldUnit {
+	var units []*fieldUnit
+	if tests["xss"] {
+		for _, field := range f.fields {
+			units = append(units, f.add(field, &fieldUnit{kind: unitXSSProbe})...)
+		}
+	}
+	if tests["sqli"] {
+		for _, field := range f.fields {
+			units = append(units, f.add(field, f.nextSQLiStage(field)...)...)
+		}
+	}
+	return units
+}
+
+// add associa as unidades ao formulário e ao campo e as conta como pendentes
+func (f *formScan) add(field *fieldScan, units ...*fieldUnit) []*fieldUnit {
+	for _, u := range units {
+		u.form = f
+		u.field = field
+	}
+	f.pending += len(units)
+	return units
+}
+
+// complete registra a unidade concluída e retorna as unidades que ela
+// libera. Quando é a última do formulário, o resultado é enviado em results.
+func (f *formScan) complete(ctx context.Context, u *fieldUnit, dropped bool, results chan<- ScanJobResult) []*fieldUnit {
+	f.units = append(f.units, u)
+	f.pending--
+
+	var next []*fieldUnit
+	if err := ctx.Err(); dropped || err != nil {
+		f.err = err
+	} else {
+		field := u.field
+		switch u.kind {
+		case unitXSSProbe:
+			for _, r := range u.reflections {
+				for _, cp := range payloadsFor(r) {
+					next = append(next, &fieldUnit{kind: unitXSSBreakout, seq: len(next), reflection: r, breakout: cp})
+				}
+			}
+			next = f.add(field, next...)
+		case unitXSSBreakout:
+			if len(u.xss) > 0 && u.xss[0].Vulnerable {
+				field.xssFound.Store(true)
+			}
+		default:
+			for _, r := range u.sqli {
+				if r.Vulnerable {
+					field.sqliFound.Store(true)
+				}
+			}
+			if field.sqliPending--; field.sqliPending == 0 {
+				next = f.add(field, f.nextSQLiStage(field)...)
+			}
+		}
+	}
+
+	if f.pending == 0 {
+		result := f.result()
+		RecordJob(ctx, formKey(f.job), result)
+		results <- result
+	}
+	return next
+}
+
+// resume completa as unidades já salvas no journal, junto com as unidades
+// que elas liberam, e retorna as que ainda precisam rodar
+func (f *formScan) resume(ctx context.Context, units []*fieldUnit, results chan<- ScanJobResult) []*fieldUnit {
+	var pending []*fieldUnit
+	for len(units) > 0 {
+		u := units[0]
+		units = units[1:]
+		if !u.restore(ctx) {
+			pending = append(pending, u)
+			continue
+		}
+		units = append(units, f.complete(ctx, u, false, results)...)
+	}
+	return pending
+}
+
+// nextSQLiStage avança o campo para o próximo estágio SQLi com unidades e as
+// retorna. Com injeção encontrada resta apenas confirmar o banco.
+func (f *formScan) nextSQLiStage(field *fieldScan) []*fieldUnit {
+	for field.sqliStage < sqliStageDone {
+		field.sqliStage++
+		if field.sqliFound.Load() && field.sqliStage < sqliStageDBMS {
+			field.sqliStage = sqliStageDBMS
+		}
+
+		var units []*fieldUnit
+		switch field.sqliStage {
+		case sqliStageError:
+			for i, p := range payloadsForDialect(DBMSUnknown) {
+				units = append(units, &fieldUnit{kind: unitSQLiError, seq: i, sqliPayload: p})
+			}
+		case sqliStageBlind:
+			units = []*fieldUnit{{kind: unitSQLiUnion}, {kind: unitSQLiBoolean}}
+		case sqliStageTime:
+			for i, tp := range techniquePayloads(SQLiTypeTime, DBMSUnknown) {
+				units = append(units, &fieldUnit{kind: unitSQLiTime, seq: i, timePayload: tp})
+			}
+		case sqliStageDBMS:
+			if found := f.sqliFinding(field); found != nil {
+				units = []*fieldUnit{{kind: unitSQLiDBMS, dbmsHint: found.DBMS}}
+			}
+		}
+
+		if len(units) > 0 {
+			field.sqliPending = len(units)
+			return units
+		}
+	}
+	return nil
+}
+
+// sqliFinding retorna a injeção do campo que o teste sequencial reportaria:
+// a primeira vulnerável na ordem dos estágios
+func (f *formScan) sqliFinding(field *fieldScan) *SQLiResult {
+	var found *SQLiResult
+	var at *fieldUnit
+	for _, u := range f.units {
+		if u.field != field || u.kind < unitSQLiError || u.kind == unitSQLiDBMS {
+			continue
+		}
+		for i := range u.sqli {
+			if u.sqli[i].Vulnerable && (at == nil || unitBefore(u, at)) {
+				found, at = &u.sqli[i], u
+			}
+		}
+	}
+	return found
+}
+
+// unitBefore ordena as unidades por campo, tipo e posição no estágio
+func unitBefore(a, b *fieldUnit) bool {
+	if a.field.index != b.field.index {
+		return a.field.index < b.field.index
+	}
+	if a.kind != b.kind {
+		return a.kind < b.kind
+	}
+	return a.seq < b.seq
+}
+
+// skip indica que o campo já foi confirmado vulnerável e a unidade não
+// precisa mais rodar
+func (u *fieldUnit) skip() bool {
+	switch u.kind {
+	case unitXSSBreakout:
+		return u.field.xssFound.Load()
+	case unitSQLiError, unitSQLiUnion, unitSQLiBoolean, unitSQLiTime:
+		return u.field.sqliFound.Load()
+	}
+	return false
+}
+
+// checkID é a verificação a que a unidade pertence
+func (u *fieldUnit) checkID() string {
+	if u.kind <= unitXSSBreakout {
+		return "xss"
+	}
+	return "sqli"
+}
+
+// run executa a unidade no worker. Um panic conta como falha da unidade.
+func (u *fieldUnit) run(ctx context.Context, client *http.Client) {
+	if u.skip() {
+		return
+	}
+
+	tracked, failures := TrackFailures(ctx)
+	u.failures = failures
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			failures.recordPanic(v)
+		}
+		u.duration = time.Since(start)
+	}()
+	form, baseURL, input := u.form.job.Form, u.form.job.BaseURL, u.field.input
+
+	switch u.kind {
+	case unitXSSProbe:
+		u.reflections, u.xss = probeXSSField(tracked, form, baseURL, input.Name, client)
+	case unitXSSBreakout:
+		if result, ok := testBreakout(tracked, form, baseURL, input.Name, u.reflection, u.breakout, client); ok {
+			u.xss = []XSSResult{result}
+		}
+	case unitSQLiError:
+		if result, ok := testErrorSQLi(tracked, form, baseURL, input.Name, u.sqliPayload, client); ok {
+			u.sqli = []SQLiResult{result}
+		}
+	case unitSQLiUnion:
+		if result, ok := testUnionSQLi(tracked, form, baseURL, input, DBMSUnknown, client); ok {
+			u.sqli = []SQLiResult{result}
+		}
+	case unitSQLiBoolean:
+		if result, ok := testBooleanSQLi(tracked, form, baseURL, input, client); ok {
+			u.sqli = []SQLiResult{result}
+		}
+	case unitSQLiTime:
+		if result, ok := testTimePayload(tracked, form, baseURL, input, u.timePayload, client); ok {
+			u.sqli = []SQLiResult{result}
+		}
+	case unitSQLiDBMS:
+		u.dbms = confirmDBMS(tracked, form, baseURL, input, u.dbmsHint, client)
+	}
+}
+
+// record salva a unidade concluída no journal do contexto. Unidades com
+// requisições que falharam não são salvas: rodam de novo no scan retomado.
+func (u *fieldUnit) record(ctx context.Context) {
+	checkID := u.checkID()
+	result := ScanJobResult{
+		XSSResults:  u.xss,
+		SQLiResults: u.sqli,
+		Reflections: u.reflections,
+		DBMS:        u.dbms,
+		Timings:     []CheckTiming{{CheckID: checkID, Runs: 1, Duration: u.duration}},
+	}
+	if u.failures != nil {
+		result.Error = u.failures.CheckError(checkID, u.form.job.BaseURL, "")
+	}
+	RecordJob(ctx, unitKey(u), result)
+}
+
+// restore preenche a saída da unidade com o resultado salvo no journal, se
+// ela já foi concluída
+func (u *fieldUnit) restore(ctx context.Context) bool {
+	saved, ok := LookupJob(ctx, unitKey(u))
+	if !ok {
+		return false
+	}
+	u.xss, u.sqli = saved.XSSResults, saved.SQLiResults
+	u.reflections, u.dbms = saved.Reflections, saved.DBMS
+	for _, t := range saved.Timings {
+		u.duration += t.Duration
+	}
+	return true
+}
+
+// result remonta o resultado do formulário na ordem dos testes sequenciais:
+// por campo, os resultados até o primeiro vulnerável de cada teste. O banco
+// do primeiro campo injetável vale para as injeções sem banco identificado
+// dos campos seguintes, como em TestSQLiDetailed. Falhas e tempos das
+// unidades são somados por teste.
+func (f *formScan) result() ScanJobResult {
+	sort.SliceStable(f.units, func(i, j int) bool { return unitBefore(f.units[i], f.units[j]) })
+
+	result := ScanJobResult{
+		FormIndex: f.job.FormIndex,
+		Form:      f.job.Form,
+		Error:     f.err,
+	}
+
+	dialect := DBMSUnknown
+	byCheck := map[string]int{}
+	var checkErrors []*CheckError
+	var timings []CheckTiming
+	var field *fieldScan
+	xssDone, sqliDone, injection := false, false, -1
+
+	closeField := func() {
+		if injection < 0 {
+			return
+		}
+		if dialect == DBMSUnknown {
+			dialect = result.SQLiResults[injection].DBMS
+		} else if result.SQLiResults[injection].DBMS == DBMSUnknown {
+			result.SQLiResults[injection].DBMS = dialect
+		}
+	}
+
+	for _, u := range f.units {
+		if u.field != field {
+			closeField()
+			field = u.field
+			xssDone, sqliDone, injection = false, false, -1
+		}
+
+		checkID := u.checkID()
+		timings = append(timings, CheckTiming{CheckID: checkID, Duration: u.duration})
+		switch {
+		case u.kind <= unitXSSBreakout:
+			for _, r := range u.xss {
+				if xssDone {
+					break
+				}
+				result.XSSResults = append(result.XSSResults, r)
+				if r.Vulnerable {
+					xssDone, result.XSSVuln = true, true
+				}
+			}
+		case u.kind == unitSQLiDBMS:
+			if injection >= 0 && u.dbms != DBMSUnknown {
+				result.SQLiResults[injection].DBMS = u.dbms
+			}
+		default:
+			for _, r := range u.sqli {
+				if sqliDone {
+					break
+				}
+				result.SQLiResults = append(result.SQLiResults, r)
+				if r.Vulnerable {
+					sqliDone, result.SQLiVuln = true, true
+					injection = len(result.SQLiResults) - 1
+				}
+			}
+		}
+
+		if u.failures == nil {
+			continue
+		}
+		inc := u.failures.Inconclusive(checkID, f.job.BaseURL, "")
+		if inc == nil {
+			continue
+		}
+		err := u.failures.CheckError(checkID, f.job.BaseURL, "").(*CheckError)
+		if i, ok := byCheck[checkID]; ok {
+			result.Inconclusive[i].Failures += inc.Failures
+			result.Inconclusive[i].Reason = inc.Reason
+			checkErrors[i].Failures += err.Failures
+			checkErrors[i].Err = err.Err
+			continue
+		}
+		byCheck[checkID] = len(result.Inconclusive)
+		result.Inconclusive = append(result.Inconclusive, *inc)
+		checkErrors = append(checkErrors, err)
+	}
+	closeField()
+
+	// Cada teste conta uma execução por formulário
+	result.Timings = SummarizeTimings(timings)
+	for i := range result.Timings {
+		result.Timings[i].Runs = 1
+		if _, ok := byCheck[result.Timings[i].CheckID]; ok {
+			result.Timings[i].Errors = 1
+		}
+	}
+	for _, err := range checkErrors {
+		result.Error = errors.Join(result.Error, err)
+	}
+
+	return result
+}
@@ -0,0 +1,195 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// formHandler simula uma busca com três campos: busca é refletido sem
+// encoding, id gera erro do MySQL e q é injetável apenas às cegas. O menu
+// fixo dá à página texto suficiente para a reflexão de busca não pesar na
+// comparação do teste booleano.
+func formHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	fmt.Fprint(w, "<html><body><nav>Início Produtos Categorias Ofertas Carrinho Minha conta Ajuda Contato</nav>")
+	fmt.Fprint(w, "<p>Resultados para "+query.Get("busca")+"</p>")
+
+	if strings.Contains(query.Get("id"), "'") {
+		fmt.Fprint(w, "<p>You have an error in your SQL syntax; check the manual that corresponds to your MySQL server</p>")
+	}
+
+	q := query.Get("q")
+	switch m := conditionRe.FindStringSubmatch(q); {
+	case q == "test", m != nil && m[1] == m[2]:
+		fmt.Fprint(w, productList)
+	case strings.Contains(q, "'"):
+		fmt.Fprint(w, "<p>Erro interno</p>")
+	default:
+		fmt.Fprint(w, "<p>Nenhum produto encontrado</p>")
+	}
+	fmt.Fprint(w, "</body></html>")
+}
+
+// summarize lista as injeções encontradas, uma por linha
+func summarize(xss []XSSResult, sqli []SQLiResult) string {
+	var got []string
+	for _, r := range xss {
+		if r.Vulnerable {
+			got = append(got, "xss "+r.Field+" "+r.Context)
+		}
+	}
+	for _, r := range sqli {
+		if r.Vulnerable {
+			got = append(got, "sqli "+r.Field+" "+r.Type+" "+string(r.DBMS))
+		}
+	}
+	return strings.Join(got, "\n")
+}
+
+func TestScanFormsMatchesSequential(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(formHandler))
+	defer server.Close()
+
+	form := Form{Method: "GET", Inputs: []Input{
+		{Name: "busca", Type: "text"},
+		{Name: "id", Type: "text"},
+		{Name: "q", Type: "text"},
+	}}
+	baseURL := server.URL + "/busca"
+
+	ctx := context.Background()
+	want := summarize(TestXSSDetailed(ctx, form, baseURL, server.Client()), TestSQLiDetailed(ctx, form, baseURL, server.Client()))
+	if !strings.Contains(want, "xss busca") || !strings.Contains(want, "sqli id") || !strings.Contains(want, "sqli q") {
+		t.Fatalf("teste sequencial não encontrou as injeções esperadas:\n%s", want)
+	}
+
+	jobs := []ScanJob{
+		{Form: form, BaseURL: baseURL, FormIndex: 0},
+		{Form: Form{Method: "GET"}, BaseURL: baseURL, FormIndex: 1},
+	}
+	var results []ScanJobResult
+	for r := range ScanForms(ctx, jobs, server.Client(), 4, map[string]bool{"xss": true, "sqli": true}) {
+		results = append(results, r)
+	}
+	if len(results) != len(jobs) {
+		t.Fatalf("%d resultados, esperado %d", len(results), len(jobs))
+	}
+
+	for _, r := range results {
+		if r.Error != nil || len(r.Inconclusive) > 0 {
+			t.Errorf("formulário %d: erro %v, inconclusivos %v", r.FormIndex, r.Error, r.Inconclusive)
+		}
+		if r.FormIndex != 0 {
+			continue
+		}
+		if got := summarize(r.XSSResults, r.SQLiResults); got != want {
+			t.Errorf("ScanForms:\n%s\nsequencial:\n%s", got, want)
+		}
+		if !r.XSSVuln || !r.SQLiVuln {
+			t.Errorf("XSSVuln = %v, SQLiVuln = %v", r.XSSVuln, r.SQLiVuln)
+		}
+	}
+}
+
+func TestScanFormsResume(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		formHandler(w, r)
+	}))
+	defer server.Close()
+
+	form := Form{Method: "GET", Inputs: []Input{
+		{Name: "busca", Type: "text"},
+		{Name: "id", Type: "text"},
+		{Name: "q", Type: "text"},
+	}}
+	jobs := []ScanJob{{Form: form, BaseURL: server.URL + "/busca"}}
+	tests := map[string]bool{"xss": true, "sqli": true}
+	scan := func(journal *mapJournal) ScanJobResult {
+		var result ScanJobResult
+		for r := range ScanForms(WithJournal(context.Background(), journal), jobs, server.Client(), 4, tests) {
+			result = r
+		}
+		return result
+	}
+
+	journal := &mapJournal{jobs: map[string]ScanJobResult{}}
+	first := scan(journal)
+	want := summarize(first.XSSResults, first.SQLiResults)
+	sent := requests.Swap(0)
+
+	// Simula a interrupção: o formulário não terminou e as unidades do
+	// campo q não foram salvas
+	delete(journal.jobs, formKey(jobs[0]))
+	for key := range journal.jobs {
+		if strings.HasPrefix(key, "unit:0:2:") {
+			delete(journal.jobs, key)
+		}
+	}
+
+	resumed := scan(journal)
+	if got := summarize(resumed.XSSResults, resumed.SQLiResults); got != want {
+		t.Errorf("retomado:\n%s\nesperado:\n%s", got, want)
+	}
+	if n := requests.Load(); n == 0 || n >= sent {
+		t.Errorf("%d requisição(ões) no scan retomado, %d no original", n, sent)
+	}
+	if _, ok := journal.Lookup(formKey(jobs[0])); !ok {
+		t.Error("formulário retomado não foi salvo")
+	}
+}
+
+func TestScanFormsTimeUnits(t *testing.T) {
+	defer func(unit time.Duration) { sqliTimeUnit = unit }(sqliTimeUnit)
+	sqliTimeUnit = 100 * time.Millisecond
+
+	// Todo payload de atraso é injetável; o resultado deve ser o do primeiro
+	// payload, como no teste sequencial, com os payloads rodando ao mesmo tempo
+	sleepRe := regexp.MustCompile(`(?:SLEEP|pg_sleep)\((\d+)\)`)
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m := sleepRe.FindStringSubmatch(r.URL.Query().Get("q")); m != nil {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+			delay, _ := strconv.Atoi(m[1])
+			time.Sleep(time.Duration(delay) * sqliTimeUnit)
+		}
+		fmt.Fprint(w, "<html><body><p>Nenhum produto encontrado</p></body></html>")
+	}))
+	defer server.Close()
+
+	jobs := []ScanJob{{Form: Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}, BaseURL: server.URL + "/busca"}}
+	var result ScanJobResult
+	for r := range ScanForms(context.Background(), jobs, server.Client(), 4, map[string]bool{"sqli": true}) {
+		result = r
+	}
+
+	if !result.SQLiVuln {
+		t.Fatalf("injeção time-based não encontrada: %+v", result.SQLiResults)
+	}
+	found := result.SQLiResults[len(result.SQLiResults)-1]
+	if found.Type != "time" || found.Description != "Blind time-based (MySQL SLEEP)" {
+		t.Errorf("injeção = %s %q, esperado o primeiro payload de atraso", found.Type, found.Description)
+	}
+	if maxRunning < 2 {
+		t.Errorf("payloads de atraso rodaram em série (máximo de %d ao mesmo tempo)", maxRunning)
+	}
+}
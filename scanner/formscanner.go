@@ -1,79 +1,400 @@
 package scanner
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
 type Form struct {
-	Action string
-	Method string
-	Inputs []string
+	Action  string
+	Method  string
+	Enctype string
+	Inputs  []Input
+	Page    string // URL da página onde o formulário foi encontrado
+	Base    string // <base href> do documento, quando presente
+	Headers map[string]string
+
+	// Botão de envio que gera esta variante do formulário (vazio no envio padrão)
+	Submitter string
+}
+
+// Input representa um campo de formulário e seus atributos
+type Input struct {
+	Name      string
+	Tag       string // input, textarea ou select
+	Type      string // text, email, number, hidden... (para select/textarea, igual à tag)
+	Value     string // Valor padrão do campo
+	Options   []string
+	Required  bool
+	Pattern   string
+	MaxLength int
+	Min       string
+	Max       string
 }
 
-func GetForms(url string, client *http.Client) ([]Form, error) {
-	resp, err := client.Get(url)
+// InputNames retorna os nomes dos campos do formulário
+func (f Form) InputNames() []string {
+	names := make([]string, 0, len(f.Inputs))
+	for _, input := range f.Inputs {
+		names = append(names, input.Name)
+	}
+	return names
+}
+
+func GetForms(ctx context.Context, url string, client *http.Client) ([]Form, error) {
+	resp, err := httpGet(ctx, client, url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return parseHTML(resp.Body)
+	return parseHTML(resp.Body, url)
 }
 
-func parseHTML(input interface{}) ([]Form, error) {
-	var htmlNode *html.Node
-	var err error
-
-	switch v := input.(type) {
-	case *http.Response:
-		htmlNode, err = html.Parse(v.Body)
-	case string:
-		htmlNode, err = html.Parse(strings.NewReader(v))
-	}
+// ParseForms extrai os formulários de um documento HTML, registrando pageURL
+// como a página de origem de cada um
+func ParseForms(r io.Reader, pageURL string) ([]Form, error) {
+	return parseHTML(r, pageURL)
+}
 
+func parseHTML(r io.Reader, pageURL string) ([]Form, error) {
+	htmlNode, err := html.Parse(r)
 	if err != nil {
 		return nil, err
 	}
 
-	var forms []Form
-	var f func(*html.Node)
-	f = func(n *html.Node) {
-		if n == nil {
-			return
+	// Primeira passada: registra os formulários e seus ids, para resolver
+	// campos que usam o atributo form="id" fora do <form>
+	var entries []*formEntry
+	byNode := make(map[*html.Node]*formEntry)
+	byID := make(map[string]*formEntry)
+	baseHref := ""
+
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "base" && baseHref == "" {
+			baseHref, _ = attrValue(n, "href")
 		}
 		if n.Type == html.ElementNode && n.Data == "form" {
-			form := Form{Method: "GET"}
-			for _, attr := range n.Attr {
-				if attr.Key == "action" {
-					form.Action = attr.Val
-				}
-				if attr.Key == "method" {
-					form.Method = strings.ToUpper(attr.Val)
-				}
+			entry := &formEntry{form: newForm(n, pageURL)}
+			entries = append(entries, entry)
+			byNode[n] = entry
+			if id, ok := attrValue(n, "id"); ok && byID[id] == nil {
+				byID[id] = entry
 			}
-			for c := n.FirstChild; c != nil; c = c.NextSibling {
-				if c.Type == html.ElementNode && (c.Data == "input" || c.Data == "textarea" || c.Data == "select") {
-					for _, attr := range c.Attr {
-						if attr.Key == "name" {
-							form.Inputs = append(form.Inputs, attr.Val)
-						}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(htmlNode)
+
+	// Segunda passada: percorre a árvore inteira associando cada campo ao
+	// formulário dono (atributo form= ou <form> ancestral)
+	var walk func(*html.Node, *formEntry)
+	walk = func(n *html.Node, current *formEntry) {
+		if n.Type == html.ElementNode {
+			if entry, ok := byNode[n]; ok {
+				current = entry
+			}
+
+			owner := current
+			if id, ok := attrValue(n, "form"); ok && isFormControl(n.Data) {
+				owner = byID[id]
+			}
+
+			if owner != nil {
+				switch {
+				case isSubmitter(n):
+					owner.submitters = append(owner.submitters, parseSubmitter(n))
+				case n.Data == "input" || n.Data == "textarea" || n.Data == "select":
+					if input, ok := parseInput(n); ok && !isNonSubmittable(input) {
+						owner.form.addInput(input)
 					}
 				}
 			}
-			forms = append(forms, form)
 		}
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			f(c)
+			walk(c, current)
 		}
 	}
-	f(htmlNode)
+	walk(htmlNode, nil)
+
+	var forms []Form
+	for _, entry := range entries {
+		entry.form.Base = baseHref
+		forms = append(forms, entry.variants()...)
+	}
 	return forms, nil
 }
 
+// formEntry acumula um formulário e seus botões de envio durante o parse
+type formEntry struct {
+	form       Form
+	submitters []submitter
+}
+
+// submitter representa um botão de envio e os overrides que ele aplica
+type submitter struct {
+	Name    string
+	Value   string
+	Type    string
+	Action  string
+	Method  string
+	Enctype string
+}
+
+func newForm(n *html.Node, pageURL string) Form {
+	form := Form{
+		Method:  "GET",
+		Enctype: "application/x-www-form-urlencoded",
+		Page:    pageURL,
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == "action" {
+			form.Action = attr.Val
+		}
+		if attr.Key == "method" {
+			form.Method = strings.ToUpper(attr.Val)
+		}
+		if attr.Key == "enctype" && attr.Val != "" {
+			form.Enctype = strings.ToLower(attr.Val)
+		}
+	}
+	return form
+}
+
+func isFormControl(tag string) bool {
+	switch tag {
+	case "input", "textarea", "select", "button":
+		return true
+	}
+	return false
+}
+
+// isSubmitter indica se o elemento envia o formulário ao ser acionado
+func isSubmitter(n *html.Node) bool {
+	typ, _ := attrValue(n, "type")
+	typ = strings.ToLower(typ)
+	switch n.Data {
+	case "button":
+		return typ == "" || typ == "submit"
+	case "input":
+		return typ == "submit" || typ == "image"
+	}
+	return false
+}
+
+// isNonSubmittable indica campos que nunca são enviados com o formulário
+func isNonSubmittable(input Input) bool {
+	return input.Type == "button" || input.Type == "reset"
+}
+
+func parseSubmitter(n *html.Node) submitter {
+	s := submitter{Type: "submit"}
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "name":
+			s.Name = attr.Val
+		case "value":
+			s.Value = attr.Val
+		case "type":
+			s.Type = strings.ToLower(attr.Val)
+		case "formaction":
+			s.Action = attr.Val
+		case "formmethod":
+			s.Method = strings.ToUpper(attr.Val)
+		case "formenctype":
+			s.Enctype = strings.ToLower(attr.Val)
+		}
+	}
+	return s
+}
+
+// variants retorna uma variante do formulário para cada botão de envio
+// distinto. Botões com name/value próprios ou com overrides de action,
+// method e enctype geram variantes separadas; botões sem efeito colapsam no
+// envio padrão.
+func (e *formEntry) variants() []Form {
+	if len(e.submitters) == 0 {
+		return []Form{e.form}
+	}
+
+	var forms []Form
+	seen := make(map[string]bool)
+
+	for _, s := range e.submitters {
+		key := strings.Join([]string{s.Name, s.Value, s.Type, s.Action, s.Method, s.Enctype}, "\x00")
+		if s.Name == "" && s.Action == "" && s.Method == "" && s.Enctype == "" {
+			key = ""
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		variant := e.form
+		variant.Inputs = append([]Input(nil), e.form.Inputs...)
+		var label []string
+
+		if s.Name != "" {
+			if s.Type == "image" {
+				// Botões image enviam as coordenadas do clique
+				variant.Inputs = append(variant.Inputs,
+					Input{Name: s.Name + ".x", Tag: "input", Type: "image", Value: "0"},
+					Input{Name: s.Name + ".y", Tag: "input", Type: "image", Value: "0"})
+				label = append(label, s.Name)
+			} else {
+				variant.Inputs = append(variant.Inputs,
+					Input{Name: s.Name, Tag: "button", Type: "submit", Value: s.Value})
+				label = append(label, s.Name+"="+s.Value)
+			}
+		}
+		if s.Action != "" {
+			variant.Action = s.Action
+			label = append(label, "formaction="+s.Action)
+		}
+		if s.Method != "" {
+			variant.Method = s.Method
+			label = append(label, "formmethod="+s.Method)
+		}
+		if s.Enctype != "" {
+			variant.Enctype = s.Enctype
+			label = append(label, "formenctype="+s.Enctype)
+		}
+		variant.Submitter = strings.Join(label, " ")
+
+		forms = append(forms, variant)
+	}
+
+	return forms
+}
+
+// parseInput extrai os metadados de um elemento input, textarea ou select
+func parseInput(n *html.Node) (Input, bool) {
+	input := Input{Tag: n.Data, Type: n.Data}
+	checked := false
+
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "name":
+			input.Name = attr.Val
+		case "type":
+			if n.Data == "input" {
+				input.Type = strings.ToLower(attr.Val)
+			}
+		case "value":
+			input.Value = attr.Val
+		case "required":
+			input.Required = true
+		case "pattern":
+			input.Pattern = attr.Val
+		case "maxlength":
+			input.MaxLength, _ = strconv.Atoi(attr.Val)
+		case "min":
+			input.Min = attr.Val
+		case "max":
+			input.Max = attr.Val
+		case "checked":
+			checked = true
+		}
+	}
+
+	if input.Name == "" {
+		return input, false
+	}
+
+	switch n.Data {
+	case "input":
+		if input.Type == "input" || input.Type == "" {
+			input.Type = "text"
+		}
+		if input.Type == "checkbox" || input.Type == "radio" {
+			if input.Value == "" {
+				input.Value = "on"
+			}
+			input.Options = []string{input.Value}
+			// Opção não marcada não é o valor padrão do campo
+			if !checked {
+				input.Value = ""
+			}
+		}
+	case "textarea":
+		input.Value = textContent(n)
+	case "select":
+		selected := ""
+		var walk func(*html.Node)
+		walk = func(c *html.Node) {
+			if c.Type == html.ElementNode && c.Data == "option" {
+				value, hasValue := attrValue(c, "value")
+				if !hasValue {
+					value = strings.TrimSpace(textContent(c))
+				}
+				input.Options = append(input.Options, value)
+				if _, ok := attrValue(c, "selected"); ok && selected == "" {
+					selected = value
+				}
+			}
+			for child := c.FirstChild; child != nil; child = child.NextSibling {
+				walk(child)
+			}
+		}
+		walk(n)
+		input.Value = selected
+	}
+
+	return input, true
+}
+
+// addInput adiciona um campo ao formulário. Radios e checkboxes com o mesmo
+// nome são agrupados em um único campo com várias opções.
+func (f *Form) addInput(input Input) {
+	if input.Type == "radio" || input.Type == "checkbox" {
+		for i := range f.Inputs {
+			existing := &f.Inputs[i]
+			if existing.Name == input.Name && existing.Type == input.Type {
+				existing.Options = append(existing.Options, input.Options...)
+				if existing.Value == "" {
+					existing.Value = input.Value
+				}
+				existing.Required = existing.Required || input.Required
+				return
+			}
+		}
+	}
+	f.Inputs = append(f.Inputs, input)
+}
+
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
 func ParseFormsFromHTML(html string) []Form {
-	forms, _ := parseHTML(html)
+	forms, _ := parseHTML(strings.NewReader(html), "")
 	return forms
 }
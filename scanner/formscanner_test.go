@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// summarizeForm resume um formulário como "METHOD action [submitter] campos"
+func summarizeForm(f Form) string {
+	return fmt.Sprintf("%s %s [%s] %s", f.Method, f.Action, f.Submitter, strings.Join(f.InputNames(), ","))
+}
+
+func parseFixture(t *testing.T, name string) []Form {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", "forms", name))
+	if err != nil {
+		t.Fatalf("erro ao abrir fixture: %v", err)
+	}
+	defer file.Close()
+
+	forms, err := ParseForms(file, "https://example.com/page")
+	if err != nil {
+		t.Fatalf("ParseForms() error = %v", err)
+	}
+	return forms
+}
+
+func TestParseFormsFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    []string
+	}{
+		{
+			name:    "Campos dentro de divs e labels (Bootstrap)",
+			fixture: "bootstrap_login.html",
+			want: []string{
+				"POST /login [] _csrf,email,password,remember",
+			},
+		},
+		{
+			name:    "Layout legado com fieldset e table",
+			fixture: "legacy_table.html",
+			want: []string{
+				"POST cadastro.php [] nome,uf,sexo,foto,obs",
+			},
+		},
+		{
+			name:    "Atributo form= do HTML5",
+			fixture: "html5_form_attribute.html",
+			want: []string{
+				"GET /search [escopo=site] q,origem,escopo",
+				"POST /newsletter [] email,idioma",
+			},
+		},
+		{
+			name:    "Botões com name/value, formaction e formmethod",
+			fixture: "multiple_submitters.html",
+			want: []string{
+				"POST /posts/42 [] id,title",
+				"POST /posts/42 [action=publish] id,title,action",
+				"POST /posts/42/delete [formaction=/posts/42/delete formmethod=POST] id,title",
+				"GET /posts/42/preview [formaction=/posts/42/preview formmethod=GET] id,title",
+				"POST /posts/42/export [export formaction=/posts/42/export] id,title,export.x,export.y",
+			},
+		},
+		{
+			name:    "Formulários do WordPress",
+			fixture: "wordpress_comment.html",
+			want: []string{
+				"POST https://blog.example.com/wp-comments-post.php [submit=Publicar comentário] comment,author,email,url,comment_post_ID,comment_parent,submit",
+				"GET https://blog.example.com/ [] s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forms := parseFixture(t, tt.fixture)
+
+			var got []string
+			for _, f := range forms {
+				got = append(got, summarizeForm(f))
+				if f.Page != "https://example.com/page" {
+					t.Errorf("Page = %q, want URL da página", f.Page)
+				}
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("formulários:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseFormsFieldMetadata(t *testing.T) {
+	forms := parseFixture(t, "legacy_table.html")
+	if len(forms) != 1 {
+		t.Fatalf("esperava 1 formulário, obteve %d", len(forms))
+	}
+	form := forms[0]
+
+	if form.Enctype != "multipart/form-data" {
+		t.Errorf("Enctype = %q, want multipart/form-data", form.Enctype)
+	}
+
+	inputs := make(map[string]Input)
+	for _, input := range form.Inputs {
+		inputs[input.Name] = input
+	}
+
+	tests := []struct {
+		field   string
+		typ     string
+		value   string
+		options string
+	}{
+		{field: "nome", typ: "text", value: ""},
+		{field: "uf", typ: "select", value: "SP", options: ",SP,RJ"},
+		{field: "sexo", typ: "radio", value: "M", options: "F,M"},
+		{field: "foto", typ: "file", value: ""},
+		{field: "obs", typ: "textarea", value: "sem observações"},
+	}
+
+	for _, tt := range tests {
+		input, ok := inputs[tt.field]
+		if !ok {
+			t.Errorf("campo %q não encontrado", tt.field)
+			continue
+		}
+		if input.Type != tt.typ {
+			t.Errorf("%s: Type = %q, want %q", tt.field, input.Type, tt.typ)
+		}
+		if input.Value != tt.value {
+			t.Errorf("%s: Value = %q, want %q", tt.field, input.Value, tt.value)
+		}
+		if got := strings.Join(input.Options, ","); got != tt.options {
+			t.Errorf("%s: Options = %q, want %q", tt.field, got, tt.options)
+		}
+	}
+
+	if inputs["nome"].MaxLength != 60 {
+		t.Errorf("nome: MaxLength = %d, want 60", inputs["nome"].MaxLength)
+	}
+}
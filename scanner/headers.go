@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,8 +17,8 @@ type SecurityHeader struct {
 }
 
 // CheckSecurityHeaders verifica headers de segurança de forma detalhada
-func CheckSecurityHeaders(url string, client *http.Client) []SecurityHeader {
-	res, err := client.Get(url)
+func CheckSecurityHeaders(ctx context.Context, url string, client *http.Client) []SecurityHeader {
+	res, err := httpGet(ctx, client, url)
 	if err != nil {
 		return []SecurityHeader{{Name: "Error", Message: err.Error(), Severity: "critical"}}
 	}
@@ -185,25 +186,3 @@ func CheckSecurityHeaders(url string, client *http.Client) []SecurityHeader {
 
 	return results
 }
-
-// PrintSecurityHeaders imprime os headers de segurança de forma formatada
-func PrintSecurityHeaders(headers []SecurityHeader) {
-	fmt.Println("\n[+] Verificação de Headers de Segurança:")
-	fmt.Println(strings.Repeat("-", 70))
-
-	for _, h := range headers {
-		icon := "[OK]"
-		if !h.Present && h.Severity != "low" {
-			icon = "[X]"
-		} else if !h.Present {
-			icon = "[!]"
-		}
-
-		fmt.Printf("%s [%s] %s\n", icon, strings.ToUpper(h.Severity), h.Name)
-		fmt.Printf("  %s\n", h.Message)
-		if h.Value != "" {
-			fmt.Printf("  Valor: %s\n", h.Value)
-		}
-		fmt.Println()
-	}
-}
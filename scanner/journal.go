@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+)
+
+// Journal guarda os jobs concluídos de um scan para que ele possa ser
+// retomado depois de interrompido. As chaves identificam o job dentro do
+// mesmo scan (mesma configuração e mesmos alvos descobertos). Os workers do
+// pool chamam Lookup e Record ao mesmo tempo: as implementações precisam ser
+// seguras para uso concorrente.
+type Journal interface {
+	// Lookup retorna o resultado salvo do job, se ele já foi concluído
+	Lookup(key string) (ScanJobResult, bool)
+	// Record salva o resultado de um job concluído
+	Record(key string, result ScanJobResult)
+}
+
+type journalKey struct{}
+
+// WithJournal retorna um contexto em que os formulários, verificações e
+// templates executados pelo pool consultam o journal antes de rodar e
+// registram nele os jobs concluídos
+func WithJournal(ctx context.Context, journal Journal) context.Context {
+	return context.WithValue(ctx, journalKey{}, journal)
+}
+
+// LookupJob retorna o resultado salvo do job no journal do contexto
+func LookupJob(ctx context.Context, key string) (ScanJobResult, bool) {
+	journal, ok := ctx.Value(journalKey{}).(Journal)
+	if !ok {
+		return ScanJobResult{}, false
+	}
+	return journal.Lookup(key)
+}
+
+// RecordJob registra o job no journal do contexto. Jobs interrompidos ou
+// com verificações que falharam não são registrados: rodam de novo quando
+// o scan for retomado.
+func RecordJob(ctx context.Context, key string, result ScanJobResult) {
+	journal, ok := ctx.Value(journalKey{}).(Journal)
+	if !ok || result.Error != nil || ctx.Err() != nil {
+		return
+	}
+	journal.Record(key, result)
+}
+
+// StepKey identifica no journal uma etapa do scan que não passa pelo pool
+// (XSS armazenado, DOM XSS)
+func StepKey(name string) string {
+	return "step:" + name
+}
+
+// formKey, unitKey, checkKey e templateKey identificam os jobs do pool. Os
+// índices são estáveis porque os alvos de um scan retomado vêm do estado
+// salvo.
+func formKey(job ScanJob) string {
+	return fmt.Sprintf("form:%d", job.FormIndex)
+}
+
+func unitKey(u *fieldUnit) string {
+	return fmt.Sprintf("unit:%d:%d:%d:%d", u.form.job.FormIndex, u.field.index, u.kind, u.seq)
+}
+
+func checkKey(c Check, target int) string {
+	return fmt.Sprintf("check:%s:%d", c.ID(), target)
+}
+
+func templateKey(t *Template, combination int) string {
+	return fmt.Sprintf("template:%s:%d", t.ID, combination)
+}
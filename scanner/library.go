@@ -0,0 +1,510 @@
+package scanner
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Categorias de payloads da biblioteca
+const (
+	CategoryXSS       = "xss"
+	CategorySQLi      = "sqli"
+	CategoryCommand   = "cmdi"
+	CategoryLFI       = "lfi"
+	CategoryTraversal = "traversal"
+	CategoryXXE       = "xxe"
+	CategoryRedirect  = "redirect"
+	CategorySSRF      = "ssrf"
+)
+
+// Categorias de assinaturas (indicadores sem payload)
+const (
+	CategorySQLError = "sqli-error"
+)
+
+var payloadCategories = map[string]bool{
+	CategoryXSS: true, CategorySQLi: true, CategoryCommand: true, CategoryLFI: true,
+	CategoryTraversal: true, CategoryXXE: true, CategoryRedirect: true, CategorySSRF: true,
+}
+
+var signatureCategories = map[string]bool{
+	CategorySQLError: true,
+}
+
+var knownDBMS = map[DBMS]bool{
+	DBMSUnknown: true, DBMSMySQL: true, DBMSPostgreSQL: true,
+	DBMSMSSQL: true, DBMSOracle: true, DBMSSQLite: true,
+}
+
+// Técnicas SQLi (type) cujos payloads são modelos preenchidos pelo teste da
+// técnica. Os demais payloads sqli são enviados como estão e a injeção é
+// reconhecida pelas mensagens de erro do banco.
+const (
+	// Condição em que %[1]d e %[2]d são iguais na versão verdadeira e
+	// diferentes na falsa
+	SQLiTypeBoolean = "boolean"
+	// Fechamento do valor e comentário em volta de %s, onde entram o ORDER BY
+	// e o UNION SELECT
+	SQLiTypeUnion = "union"
+	// Atraso de %d segundos
+	SQLiTypeTime = "time"
+	// Condição verdadeira apenas no banco indicado em dbms
+	SQLiTypeDBMS = "dbms"
+)
+
+var sqliTemplateTypes = map[string]bool{
+	SQLiTypeBoolean: true, SQLiTypeUnion: true, SQLiTypeTime: true, SQLiTypeDBMS: true,
+}
+
+var knownContexts = map[ReflectionContext]bool{
+	ContextText: true, ContextRCDATA: true, ContextAttrQuoted: true, ContextAttrUnquoted: true,
+	ContextURLAttr: true, ContextScript: true, ContextStyle: true, ContextComment: true,
+}
+
+// Verificações de breakout XSS (check); sem check, o elemento injetado
+var breakoutChecks = map[string]breakoutCheck{
+	"":               checkInjectedElement,
+	"element":        checkInjectedElement,
+	"javascript-url": checkJavascriptURL,
+	"script-string":  checkScriptString,
+}
+
+// PayloadDef é um payload da biblioteca com os indicadores esperados na
+// resposta quando o alvo é vulnerável.
+//
+// Os payloads xss são breakouts para os contextos de reflexão em Context:
+// {{t}} é substituído por um token único e {{q}}/{{tag}} pelas aspas e pelo
+// elemento da reflexão, e Check diz como o breakout é confirmado no DOM.
+// Nos payloads sqli, Type escolhe a técnica (SQLiTypeBoolean etc.).
+type PayloadDef struct {
+	ID          string              `json:"id"`
+	Category    string              `json:"category"`
+	Payload     string              `json:"payload"`
+	Description string              `json:"description,omitempty"`
+	Type        string              `json:"type,omitempty"` // Técnica, ex.: error, boolean
+	Safety      string              `json:"safety"`         // safe, intrusive ou destructive
+	DBMS        DBMS                `json:"dbms,omitempty"`
+	Context     []ReflectionContext `json:"context,omitempty"`
+	Check       string              `json:"check,omitempty"` // element, javascript-url ou script-string
+	Indicators  []string            `json:"indicators,omitempty"`
+	Regex       []string            `json:"regex,omitempty"`
+
+	level   SafetyLevel
+	check   breakoutCheck
+	regexes []*regexp.Regexp
+}
+
+// Signature agrupa indicadores de detecção que não dependem de um payload,
+// como mensagens de erro de banco
+type Signature struct {
+	ID         string   `json:"id"`
+	Category   string   `json:"category"`
+	DBMS       DBMS     `json:"dbms,omitempty"`
+	Indicators []string `json:"indicators,omitempty"`
+	Regex      []string `json:"regex,omitempty"`
+
+	regexes []*regexp.Regexp
+}
+
+// libraryFile é o formato de cada arquivo JSON da biblioteca
+type libraryFile struct {
+	Payloads   []PayloadDef `json:"payloads"`
+	Signatures []Signature  `json:"signatures"`
+}
+
+// Library é o conjunto de payloads e assinaturas usado nos testes
+type Library struct {
+	Payloads   []PayloadDef
+	Signatures []Signature
+}
+
+//go:embed payloads/*.json
+var defaultPayloadFS embed.FS
+
+var library = mustLoadDefaultLibrary()
+
+// SetLibrary define a biblioteca de payloads usada nos testes
+func SetLibrary(lib *Library) {
+	library = lib
+}
+
+// CurrentLibrary retorna a biblioteca de payloads em uso
+func CurrentLibrary() *Library {
+	return library
+}
+
+func mustLoadDefaultLibrary() *Library {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		panic(fmt.Sprintf("biblioteca de payloads embutida inválida: %v", err))
+	}
+	return lib
+}
+
+// DefaultLibrary carrega os payloads embutidos no binário
+func DefaultLibrary() (*Library, error) {
+	lib := &Library{}
+	err := fs.WalkDir(defaultPayloadFS, "payloads", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := defaultPayloadFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return lib.add(path, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lib, nil
+}
+
+// LoadLibrary carrega os payloads embutidos e os arquivos .json de dir.
+// Entradas com o mesmo id de uma embutida a substituem; as demais são
+// acrescentadas.
+func LoadLibrary(dir string) (*Library, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return lib, nil
+	}
+
+	files, err := libraryFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := lib.add(path, data); err != nil {
+			return nil, err
+		}
+	}
+	return lib, nil
+}
+
+// ValidateLibraryFiles valida arquivos ou diretórios de payloads sem parar no
+// primeiro erro. IDs repetidos entre arquivos também são reportados.
+func ValidateLibraryFiles(paths ...string) []error {
+	var errs []error
+	lib := &Library{}
+
+	for _, p := range paths {
+		files, err := libraryFiles(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, lib.validate(path, data)...)
+		}
+	}
+	return errs
+}
+
+// libraryFiles retorna os arquivos .json de um diretório (ou o próprio
+// arquivo), em ordem alfabética
+func libraryFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// add valida o arquivo e incorpora suas entradas, retornando o primeiro erro
+func (l *Library) add(path string, data []byte) error {
+	override := &Library{}
+	if errs := override.validate(path, data); len(errs) > 0 {
+		return errs[0]
+	}
+
+	for _, p := range override.Payloads {
+		if i := l.payloadIndex(p.ID); i >= 0 {
+			l.Payloads[i] = p
+		} else {
+			l.Payloads = append(l.Payloads, p)
+		}
+	}
+	for _, s := range override.Signatures {
+		if i := l.signatureIndex(s.ID); i >= 0 {
+			l.Signatures[i] = s
+		} else {
+			l.Signatures = append(l.Signatures, s)
+		}
+	}
+	return nil
+}
+
+// validate decodifica e valida um arquivo, acrescentando as entradas válidas
+// à biblioteca. IDs já presentes na biblioteca são reportados como repetidos.
+// Campos desconhecidos são rejeitados para pegar erros de digitação.
+func (l *Library) validate(path string, data []byte) []error {
+	var file libraryFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return []error{fmt.Errorf("%s: JSON inválido: %w", path, err)}
+	}
+
+	var errs []error
+	fail := func(id string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s: %s", path, id, fmt.Sprintf(format, args...)))
+	}
+
+	for i := range file.Payloads {
+		p := &file.Payloads[i]
+		before := len(errs)
+		id := p.ID
+		if id == "" {
+			id = fmt.Sprintf("payload %d", i+1)
+			fail(id, "id é obrigatório")
+		} else if l.payloadIndex(id) >= 0 {
+			fail(id, "id repetido")
+		}
+		if !payloadCategories[p.Category] {
+			fail(id, "categoria desconhecida %q", p.Category)
+		}
+		if p.Payload == "" {
+			fail(id, "payload é obrigatório")
+		}
+		level, err := ParseSafetyLevel(p.Safety)
+		if err != nil {
+			fail(id, "%v", err)
+		}
+		p.level = level
+		if !knownDBMS[p.DBMS] {
+			fail(id, "dbms desconhecido %q", p.DBMS)
+		}
+		for _, msg := range p.checkFormat() {
+			fail(id, "%s", msg)
+		}
+		regexes, err := compileRegexes(p.Regex)
+		if err != nil {
+			fail(id, "%v", err)
+		}
+		p.regexes = regexes
+
+		if len(errs) == before {
+			l.Payloads = append(l.Payloads, *p)
+		}
+	}
+
+	for i := range file.Signatures {
+		s := &file.Signatures[i]
+		before := len(errs)
+		id := s.ID
+		if id == "" {
+			id = fmt.Sprintf("assinatura %d", i+1)
+			fail(id, "id é obrigatório")
+		} else if l.signatureIndex(id) >= 0 {
+			fail(id, "id repetido")
+		}
+		if !signatureCategories[s.Category] {
+			fail(id, "categoria desconhecida %q", s.Category)
+		}
+		if len(s.Indicators) == 0 && len(s.Regex) == 0 {
+			fail(id, "indicators ou regex são obrigatórios")
+		}
+		if !knownDBMS[s.DBMS] {
+			fail(id, "dbms desconhecido %q", s.DBMS)
+		}
+		regexes, err := compileRegexes(s.Regex)
+		if err != nil {
+			fail(id, "%v", err)
+		}
+		s.regexes = regexes
+
+		if len(errs) == before {
+			l.Signatures = append(l.Signatures, *s)
+		}
+	}
+
+	return errs
+}
+
+// checkFormat valida os campos que dependem da categoria: contextos e
+// verificação dos breakouts XSS e os marcadores dos modelos das técnicas SQLi
+func (p *PayloadDef) checkFormat() []string {
+	var problems []string
+	if p.Category != CategoryXSS {
+		if len(p.Context) > 0 || p.Check != "" {
+			problems = append(problems, "context e check só se aplicam a payloads xss")
+		}
+	} else {
+		if len(p.Context) == 0 {
+			problems = append(problems, "context é obrigatório para payloads xss")
+		}
+		for _, c := range p.Context {
+			if !knownContexts[c] {
+				problems = append(problems, fmt.Sprintf("contexto desconhecido %q", c))
+			}
+		}
+		check, ok := breakoutChecks[p.Check]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("check desconhecido %q", p.Check))
+		}
+		p.check = check
+		if !strings.Contains(p.Payload, "{{t}}") {
+			problems = append(problems, "payload xss precisa do marcador {{t}}")
+		}
+	}
+
+	if p.Category != CategorySQLi {
+		return problems
+	}
+	switch p.Type {
+	case SQLiTypeBoolean:
+		if !strings.Contains(p.Payload, "%[1]d") || !strings.Contains(p.Payload, "%[2]d") {
+			problems = append(problems, "payload boolean precisa de %[1]d e %[2]d")
+		}
+	case SQLiTypeUnion:
+		if strings.Count(p.Payload, "%s") != 1 {
+			problems = append(problems, "payload union precisa de um %s")
+		}
+	case SQLiTypeTime:
+		if strings.Count(p.Payload, "%d") != 1 {
+			problems = append(problems, "payload time precisa de um %d")
+		}
+	case SQLiTypeDBMS:
+		if p.DBMS == DBMSUnknown {
+			problems = append(problems, "payload dbms precisa do banco em dbms")
+		}
+	}
+	return problems
+}
+
+func (l *Library) payloadIndex(id string) int {
+	for i, p := range l.Payloads {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *Library) signatureIndex(id string) int {
+	for i, s := range l.Signatures {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	var regexes []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("regex inválida %q: %w", pattern, err)
+		}
+		regexes = append(regexes, re)
+	}
+	return regexes, nil
+}
+
+// ByCategory retorna os payloads da categoria permitidos no nível de
+// segurança atual, na ordem da biblioteca
+func (l *Library) ByCategory(category string) []PayloadDef {
+	var payloads []PayloadDef
+	for _, p := range l.Payloads {
+		if p.Category == category && Allowed(p.level) {
+			payloads = append(payloads, p)
+		}
+	}
+	return payloads
+}
+
+// SignaturesFor retorna as assinaturas da categoria. As específicas de um
+// banco vêm antes das genéricas.
+func (l *Library) SignaturesFor(category string) []Signature {
+	var specific, generic []Signature
+	for _, s := range l.Signatures {
+		if s.Category != category {
+			continue
+		}
+		if s.DBMS == DBMSUnknown {
+			generic = append(generic, s)
+		} else {
+			specific = append(specific, s)
+		}
+	}
+	return append(specific, generic...)
+}
+
+// Level retorna o nível de segurança do payload
+func (p PayloadDef) Level() SafetyLevel {
+	return p.level
+}
+
+// IsTemplate informa se o payload é um modelo preenchido pelo teste (breakout
+// XSS ou técnica SQLi) em vez de um valor enviado como está
+func (p PayloadDef) IsTemplate() bool {
+	return p.Category == CategoryXSS || (p.Category == CategorySQLi && sqliTemplateTypes[p.Type])
+}
+
+// Match procura os indicadores do payload na resposta e retorna o primeiro
+// encontrado. Os indicadores literais diferenciam maiúsculas: trechos como
+// "root:x:0:0" ou "Volume Serial Number" aparecem exatamente assim na saída.
+func (p PayloadDef) Match(body string) (string, bool) {
+	return matchIndicators(body, p.Indicators, p.regexes, false)
+}
+
+// Match procura os indicadores da assinatura na resposta, sem diferenciar
+// maiúsculas nos indicadores literais
+func (s Signature) Match(body string) (string, bool) {
+	return matchIndicators(body, s.Indicators, s.regexes, true)
+}
+
+// matchIndicators procura os indicadores literais e depois as expressões
+// regulares; com foldCase os literais são comparados sem diferenciar
+// maiúsculas
+func matchIndicators(body string, indicators []string, regexes []*regexp.Regexp, foldCase bool) (string, bool) {
+	haystack := body
+	if foldCase {
+		haystack = strings.ToLower(body)
+	}
+	for _, indicator := range indicators {
+		needle := indicator
+		if foldCase {
+			needle = strings.ToLower(indicator)
+		}
+		if strings.Contains(haystack, needle) {
+			return indicator, true
+		}
+	}
+	for _, re := range regexes {
+		if m := re.FindString(body); m != "" {
+			return m, true
+		}
+	}
+	return "", false
+}
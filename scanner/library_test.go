@@ -0,0 +1,163 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLibraryValid(t *testing.T) {
+	if errs := ValidateLibraryFiles("payloads"); len(errs) > 0 {
+		t.Fatalf("biblioteca embutida inválida: %v", errs)
+	}
+}
+
+func TestLoadLibraryOverride(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{
+  "payloads": [
+    {"id": "xss-text-img", "category": "xss", "payload": "<details open ontoggle=alert(1) data-{{t}}>", "safety": "safe", "context": ["html-text"]},
+    {"id": "cmdi-custom", "category": "cmdi", "payload": "; id", "safety": "intrusive", "regex": ["uid=[0-9]+"]}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "custom.json"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	def, err := DefaultLibrary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, err := LoadLibrary(dir)
+	if err != nil {
+		t.Fatalf("LoadLibrary() erro: %v", err)
+	}
+
+	if len(lib.Payloads) != len(def.Payloads)+1 {
+		t.Errorf("len(Payloads) = %d, want %d", len(lib.Payloads), len(def.Payloads)+1)
+	}
+	p := lib.Payloads[lib.payloadIndex("xss-text-img")]
+	if p.Payload != "<details open ontoggle=alert(1) data-{{t}}>" {
+		t.Errorf("payload não substituído: %q", p.Payload)
+	}
+	p = lib.Payloads[lib.payloadIndex("cmdi-custom")]
+	if indicator, found := p.Match("uid=33(www-data)"); !found || indicator != "uid=33" {
+		t.Errorf("Match() = %q, %v", indicator, found)
+	}
+}
+
+func TestValidateLibraryFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"JSON inválido", `{"payloads": [`, "JSON inválido"},
+		{"Campo desconhecido", `{"payloads": [{"id": "a", "category": "cmdi", "payload": "x", "safety": "safe", "regexp": ["x"]}]}`, "JSON inválido"},
+		{"Sem id", `{"payloads": [{"category": "cmdi", "payload": "x", "safety": "safe"}]}`, "id é obrigatório"},
+		{"Id repetido", `{"payloads": [{"id": "a", "category": "cmdi", "payload": "x", "safety": "safe"}, {"id": "a", "category": "cmdi", "payload": "y", "safety": "safe"}]}`, "id repetido"},
+		{"Categoria desconhecida", `{"payloads": [{"id": "a", "category": "rce", "payload": "x", "safety": "safe"}]}`, "categoria desconhecida"},
+		{"Sem classificação", `{"payloads": [{"id": "a", "category": "cmdi", "payload": "x"}]}`, "nível de segurança inválido"},
+		{"Banco desconhecido", `{"payloads": [{"id": "a", "category": "sqli", "payload": "x", "safety": "safe", "dbms": "DB2"}]}`, "dbms desconhecido"},
+		{"Regex inválida", `{"signatures": [{"id": "s", "category": "sqli-error", "regex": ["("]}]}`, "regex inválida"},
+		{"Assinatura sem indicadores", `{"signatures": [{"id": "s", "category": "sqli-error"}]}`, "indicators ou regex"},
+		{"XSS sem contexto", `{"payloads": [{"id": "a", "category": "xss", "payload": "<b data-{{t}}>", "safety": "safe"}]}`, "context é obrigatório"},
+		{"XSS com contexto desconhecido", `{"payloads": [{"id": "a", "category": "xss", "payload": "<b data-{{t}}>", "safety": "safe", "context": ["css"]}]}`, "contexto desconhecido"},
+		{"XSS sem token", `{"payloads": [{"id": "a", "category": "xss", "payload": "<script>alert(1)</script>", "safety": "safe", "context": ["html-text"]}]}`, "marcador {{t}}"},
+		{"XSS com check desconhecido", `{"payloads": [{"id": "a", "category": "xss", "payload": "<b data-{{t}}>", "safety": "safe", "context": ["html-text"], "check": "alert"}]}`, "check desconhecido"},
+		{"Contexto fora de xss", `{"payloads": [{"id": "a", "category": "cmdi", "payload": "x", "safety": "safe", "context": ["html-text"]}]}`, "só se aplicam a payloads xss"},
+		{"Boolean sem marcadores", `{"payloads": [{"id": "a", "category": "sqli", "type": "boolean", "payload": "' AND 1=1", "safety": "safe"}]}`, "%[1]d e %[2]d"},
+		{"Union sem %s", `{"payloads": [{"id": "a", "category": "sqli", "type": "union", "payload": "'-- ", "safety": "safe"}]}`, "um %s"},
+		{"Time sem %d", `{"payloads": [{"id": "a", "category": "sqli", "type": "time", "payload": "' AND SLEEP(5)-- ", "safety": "safe"}]}`, "um %d"},
+		{"Sonda sem banco", `{"payloads": [{"id": "a", "category": "sqli", "type": "dbms", "payload": "' AND 1=1-- ", "safety": "safe"}]}`, "banco em dbms"},
+		{"Válido", `{"payloads": [{"id": "a", "category": "cmdi", "payload": "x", "safety": "safe"}]}`, ""},
+		{"XSS válido", `{"payloads": [{"id": "a", "category": "xss", "payload": "javascript:alert(1)//{{t}}", "safety": "safe", "context": ["url-attr"], "check": "javascript-url"}]}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lib.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			errs := ValidateLibraryFiles(path)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("erros inesperados: %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("erros = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Breakouts de um arquivo da biblioteca são usados no teste XSS
+func TestLibraryBreakouts(t *testing.T) {
+	defer SetLibrary(CurrentLibrary())
+
+	// Filtro que remove img e svg, mas deixa passar details
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := strings.NewReplacer("<img", "", "<svg", "").Replace(r.URL.Query().Get("q"))
+		fmt.Fprint(w, "<html><body><p>"+q+"</p></body></html>")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	custom := `{"payloads": [{"id": "xss-text-details", "category": "xss", "payload": "<details open ontoggle=alert(1) data-{{t}}>", "description": "details ontoggle", "safety": "safe", "context": ["html-text"]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "custom.json"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lib, err := LoadLibrary(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+	vulnerable := func() string {
+		for _, r := range TestXSSDetailed(context.Background(), form, server.URL+"/", server.Client()) {
+			if r.Vulnerable {
+				return r.Description
+			}
+		}
+		return ""
+	}
+
+	if got := vulnerable(); got != "" {
+		t.Fatalf("biblioteca embutida passou pelo filtro com %q", got)
+	}
+	SetLibrary(lib)
+	if got := vulnerable(); got != "details ontoggle" {
+		t.Errorf("breakout confirmado = %q, want o payload do arquivo", got)
+	}
+}
+
+func TestLibraryMatchCase(t *testing.T) {
+	payload := PayloadDef{Indicators: []string{"root:x:0:0"}}
+	signature := Signature{Indicators: []string{"you have an error in your sql syntax"}}
+
+	tests := []struct {
+		name  string
+		match func(string) (string, bool)
+		body  string
+		want  bool
+	}{
+		{"Payload com o indicador exato", payload.Match, "root:x:0:0:root:/root:/bin/bash", true},
+		{"Payload diferencia maiúsculas", payload.Match, "ROOT:X:0:0", false},
+		{"Assinatura em minúsculas", signature.Match, "you have an error in your sql syntax near", true},
+		{"Assinatura ignora maiúsculas", signature.Match, "You have an error in your SQL syntax near", true},
+	}
+	for _, tt := range tests {
+		if _, got := tt.match(tt.body); got != tt.want {
+			t.Errorf("%s: Match(%q) = %v, want %v", tt.name, tt.body, got, tt.want)
+		}
+	}
+}
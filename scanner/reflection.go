@@ -0,0 +1,299 @@
+package scanner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ReflectionContext identifica onde um valor refletido aparece no HTML
+type ReflectionContext string
+
+const (
+	ContextText         ReflectionContext = "html-text"
+	ContextRCDATA       ReflectionContext = "rcdata" // <textarea>, <title>
+	ContextAttrQuoted   ReflectionContext = "attr-quoted"
+	ContextAttrUnquoted ReflectionContext = "attr-unquoted"
+	ContextURLAttr      ReflectionContext = "url-attr"
+	ContextScript       ReflectionContext = "script"
+	ContextStyle        ReflectionContext = "style"
+	ContextComment      ReflectionContext = "comment"
+)
+
+// Reflection descreve uma ocorrência do canary na resposta
+type Reflection struct {
+	Context ReflectionContext
+	Tag     string // Elemento onde o valor apareceu
+	Attr    string // Atributo, quando refletido em atributo
+	Quote   string // Aspas que delimitam o atributo ou a string JavaScript
+}
+
+// Atributos cujo valor é interpretado como URL
+var urlAttrs = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"data":       true,
+	"poster":     true,
+	"xlink:href": true,
+}
+
+// newCanary gera um marcador único em minúsculas, que sobrevive à
+// normalização de nomes de atributos feita pelo parser HTML
+func newCanary() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "fdc" + hex.EncodeToString(b)
+}
+
+// findReflections localiza o canary na resposta e classifica o contexto de
+// cada ocorrência
+func findReflections(body, canary string) []Reflection {
+	var reflections []Reflection
+	seen := make(map[Reflection]bool)
+	add := func(r Reflection) {
+		if !seen[r] {
+			seen[r] = true
+			reflections = append(reflections, r)
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(body))
+	rawTag := ""
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return reflections
+
+		case html.TextToken:
+			if !strings.Contains(string(z.Raw()), canary) {
+				continue
+			}
+			switch rawTag {
+			case "script":
+				text := string(z.Raw())
+				quote := jsStringQuote(text, strings.Index(text, canary))
+				add(Reflection{Context: ContextScript, Tag: rawTag, Quote: quote})
+			case "style":
+				add(Reflection{Context: ContextStyle, Tag: rawTag})
+			case "textarea", "title":
+				add(Reflection{Context: ContextRCDATA, Tag: rawTag})
+			default:
+				add(Reflection{Context: ContextText})
+			}
+
+		case html.CommentToken:
+			if strings.Contains(string(z.Raw()), canary) {
+				add(Reflection{Context: ContextComment})
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			raw := string(z.Raw())
+			name, hasAttr := z.TagName()
+			tag := string(name)
+
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if !strings.Contains(string(val), canary) {
+					continue
+				}
+				add(attrReflection(tag, string(key), string(val), raw, canary))
+			}
+
+			if tt == html.StartTagToken {
+				switch tag {
+				case "script", "style", "textarea", "title":
+					rawTag = tag
+				}
+			}
+
+		case html.EndTagToken:
+			rawTag = ""
+		}
+	}
+}
+
+// attrReflection classifica uma reflexão em valor de atributo
+func attrReflection(tag, key, val, raw, canary string) Reflection {
+	r := Reflection{Tag: tag, Attr: key, Context: ContextAttrUnquoted}
+
+	re := regexp.MustCompile(`(?i)(?:^|\s)` + regexp.QuoteMeta(key) + `\s*=\s*(["']?)`)
+	if m := re.FindStringSubmatch(raw); m != nil && m[1] != "" {
+		r.Quote = m[1]
+		r.Context = ContextAttrQuoted
+	}
+
+	// Valor controlado desde o início de um atributo de URL permite javascript:
+	if urlAttrs[key] && strings.HasPrefix(strings.TrimSpace(val), canary) {
+		r.Context = ContextURLAttr
+	}
+	return r
+}
+
+// jsStringQuote retorna o delimitador da string JavaScript que contém a
+// posição idx do script, ou "" se a posição estiver fora de strings
+func jsStringQuote(script string, idx int) string {
+	quote := byte(0)
+	for i := 0; i < idx && i < len(script); i++ {
+		ch := script[i]
+		switch {
+		case quote != 0 && ch == '\\':
+			i++ // Pula o caractere escapado
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote == 0 && (ch == '\'' || ch == '"' || ch == '`'):
+			quote = ch
+		case quote == 0 && ch == '/' && i+1 < len(script) && script[i+1] == '/':
+			// Comentário de linha
+			for i < idx && script[i] != '\n' {
+				i++
+			}
+		}
+	}
+	if quote == 0 {
+		return ""
+	}
+	return string(quote)
+}
+
+// breakoutCheck define como confirmar que um payload escapou do contexto
+type breakoutCheck int
+
+const (
+	checkInjectedElement breakoutCheck = iota // Elemento/atributo com handler injetado
+	checkJavascriptURL                        // Atributo de URL com javascript:
+	checkScriptString                         // Quebra de string dentro de <script>
+)
+
+// contextPayload é um payload de breakout da biblioteca (categoria xss).
+// {{t}} é substituído por um token único e {{q}}/{{tag}} pelas aspas e pelo
+// elemento da reflexão.
+type contextPayload struct {
+	Template    string
+	Description string
+	Check       breakoutCheck
+}
+
+// payloadsFor retorna os payloads da biblioteca marcados para o contexto da
+// reflexão e permitidos no nível de segurança atual. Atributos de URL também
+// recebem os breakouts de atributo comuns.
+func payloadsFor(r Reflection) []contextPayload {
+	contexts := []ReflectionContext{r.Context}
+	if r.Context == ContextURLAttr {
+		if r.Quote != "" {
+			contexts = append(contexts, ContextAttrQuoted)
+		} else {
+			contexts = append(contexts, ContextAttrUnquoted)
+		}
+	}
+
+	available := library.ByCategory(CategoryXSS)
+	seen := map[string]bool{}
+	var payloads []contextPayload
+	for _, c := range contexts {
+		for _, p := range available {
+			if seen[p.ID] || !p.appliesTo(c) {
+				continue
+			}
+			seen[p.ID] = true
+			payloads = append(payloads, contextPayload{Template: p.Payload, Description: p.Description, Check: p.check})
+		}
+	}
+	return payloads
+}
+
+// appliesTo informa se o breakout está marcado para o contexto
+func (p PayloadDef) appliesTo(c ReflectionContext) bool {
+	for _, pc := range p.Context {
+		if pc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// render retorna o payload e a descrição com os marcadores substituídos
+func (p contextPayload) render(r Reflection, token string) (string, string) {
+	replacer := strings.NewReplacer("{{t}}", token, "{{q}}", r.Quote, "{{tag}}", r.Tag)
+	return replacer.Replace(p.Template), replacer.Replace(p.Description)
+}
+
+// confirmBreakout reinterpreta a resposta e verifica se o payload de fato
+// saiu do contexto original
+func confirmBreakout(body, payload, token string, check breakoutCheck, r Reflection) bool {
+	switch check {
+	case checkJavascriptURL:
+		return findElement(body, func(n *html.Node) bool {
+			for _, a := range n.Attr {
+				if a.Key == r.Attr && strings.Contains(a.Val, token) &&
+					strings.HasPrefix(strings.ToLower(strings.TrimSpace(a.Val)), "javascript:") {
+					return true
+				}
+			}
+			return false
+		})
+
+	case checkScriptString:
+		return findElement(body, func(n *html.Node) bool {
+			if n.Data != "script" {
+				return false
+			}
+			text := textContent(n)
+			idx := strings.Index(text, payload)
+			if idx < 0 {
+				return false
+			}
+			// Aspas escapadas com barra invertida não quebram a string
+			backslashes := 0
+			for i := idx - 1; i >= 0 && text[i] == '\\'; i-- {
+				backslashes++
+			}
+			return backslashes%2 == 0
+		})
+
+	default:
+		marker := "data-" + token
+		return findElement(body, func(n *html.Node) bool {
+			hasMarker, hasHandler := false, false
+			for _, a := range n.Attr {
+				if a.Key == marker {
+					hasMarker = true
+				}
+				if strings.HasPrefix(a.Key, "on") {
+					hasHandler = true
+				}
+			}
+			return hasMarker && hasHandler
+		})
+	}
+}
+
+// findElement percorre o DOM da resposta procurando um elemento que satisfaça
+// o predicado
+func findElement(body string, match func(*html.Node) bool) bool {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	var f func(*html.Node) bool
+	f = func(n *html.Node) bool {
+		if n.Type == html.ElementNode && match(n) {
+			return true
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if f(c) {
+				return true
+			}
+		}
+		return false
+	}
+	return f(doc)
+}
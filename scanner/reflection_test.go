@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFindReflections(t *testing.T) {
+	const c = "fdc0123456789ab"
+
+	tests := []struct {
+		name string
+		body string
+		want []Reflection
+	}{
+		{
+			name: "Texto de elemento",
+			body: "<p>Busca por " + c + "</p>",
+			want: []Reflection{{Context: ContextText}},
+		},
+		{
+			name: "Atributo com aspas duplas",
+			body: `<input name="q" value="` + c + `">`,
+			want: []Reflection{{Context: ContextAttrQuoted, Tag: "input", Attr: "value", Quote: `"`}},
+		},
+		{
+			name: "Atributo com aspas simples",
+			body: `<input name='q' value='x` + c + `'>`,
+			want: []Reflection{{Context: ContextAttrQuoted, Tag: "input", Attr: "value", Quote: "'"}},
+		},
+		{
+			name: "Atributo sem aspas",
+			body: `<div class=` + c + `>x</div>`,
+			want: []Reflection{{Context: ContextAttrUnquoted, Tag: "div", Attr: "class"}},
+		},
+		{
+			name: "Atributo de URL",
+			body: `<a href="` + c + `">link</a>`,
+			want: []Reflection{{Context: ContextURLAttr, Tag: "a", Attr: "href", Quote: `"`}},
+		},
+		{
+			name: "Atributo de URL com prefixo fixo não é contexto de URL",
+			body: `<a href="/busca?q=` + c + `">link</a>`,
+			want: []Reflection{{Context: ContextAttrQuoted, Tag: "a", Attr: "href", Quote: `"`}},
+		},
+		{
+			name: "Bloco de script",
+			body: `<script>var q = '` + c + `';</script>`,
+			want: []Reflection{{Context: ContextScript, Tag: "script", Quote: "'"}},
+		},
+		{
+			name: "Script fora de string",
+			body: `<script>var s = "a'b"; var n = ` + c + `;</script>`,
+			want: []Reflection{{Context: ContextScript, Tag: "script"}},
+		},
+		{
+			name: "Bloco de style",
+			body: `<style>.x { color: ` + c + ` }</style>`,
+			want: []Reflection{{Context: ContextStyle, Tag: "style"}},
+		},
+		{
+			name: "Comentário",
+			body: `<!-- debug: ` + c + ` -->`,
+			want: []Reflection{{Context: ContextComment}},
+		},
+		{
+			name: "Textarea",
+			body: `<textarea>` + c + `</textarea>`,
+			want: []Reflection{{Context: ContextRCDATA, Tag: "textarea"}},
+		},
+		{
+			name: "Vários contextos",
+			body: `<title>` + c + `</title><p>` + c + `</p><input value="` + c + `">`,
+			want: []Reflection{
+				{Context: ContextRCDATA, Tag: "title"},
+				{Context: ContextText},
+				{Context: ContextAttrQuoted, Tag: "input", Attr: "value", Quote: `"`},
+			},
+		},
+		{
+			name: "Sem reflexão",
+			body: `<p>nada aqui</p>`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findReflections(tt.body, c)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("findReflections() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXSSDetailedContexts(t *testing.T) {
+	tests := []struct {
+		name        string
+		render      func(q string) string
+		wantVuln    bool
+		wantContext ReflectionContext
+	}{
+		{
+			name:        "Texto sem encoding",
+			render:      func(q string) string { return "<p>" + q + "</p>" },
+			wantVuln:    true,
+			wantContext: ContextText,
+		},
+		{
+			name:     "Texto com encoding",
+			render:   func(q string) string { return "<p>" + html.EscapeString(q) + "</p>" },
+			wantVuln: false,
+		},
+		{
+			name: "Comentário sem encoding",
+			render: func(q string) string {
+				return "<!-- " + q + " --><p>ok</p>"
+			},
+			wantVuln:    true,
+			wantContext: ContextComment,
+		},
+		{
+			name: "Textarea que remove fechamento de tag",
+			render: func(q string) string {
+				return "<textarea>" + strings.ReplaceAll(q, "</", "") + "</textarea>"
+			},
+			wantVuln: false,
+		},
+		{
+			name:        "Atributo com aspas sem encoding",
+			render:      func(q string) string { return `<input value="` + q + `">` },
+			wantVuln:    true,
+			wantContext: ContextAttrQuoted,
+		},
+		{
+			name: "Atributo com aspas escapadas",
+			render: func(q string) string {
+				return `<input value="` + strings.ReplaceAll(q, `"`, "&quot;") + `">`
+			},
+			wantVuln: false,
+		},
+		{
+			name: "String JavaScript com aspas escapadas e < filtrado",
+			render: func(q string) string {
+				q = strings.NewReplacer("'", `\'`, "<", "").Replace(q)
+				return "<script>var q = '" + q + "';</script>"
+			},
+			wantVuln: false,
+		},
+		{
+			name: "String JavaScript sem escape",
+			render: func(q string) string {
+				return "<script>var q = '" + strings.ReplaceAll(q, "<", "") + "';</script>"
+			},
+			wantVuln:    true,
+			wantContext: ContextScript,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				fmt.Fprint(w, "<html><body>"+tt.render(r.URL.Query().Get("q"))+"</body></html>")
+			}))
+			defer server.Close()
+
+			form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+			results := TestXSSDetailed(context.Background(), form, server.URL+"/", server.Client())
+
+			var found *XSSResult
+			for i := range results {
+				if results[i].Vulnerable {
+					found = &results[i]
+					break
+				}
+			}
+
+			if (found != nil) != tt.wantVuln {
+				t.Fatalf("vulnerável = %v, want %v (%+v)", found != nil, tt.wantVuln, results)
+			}
+			if found != nil && found.Context != string(tt.wantContext) {
+				t.Errorf("Context = %q, want %q", found.Context, tt.wantContext)
+			}
+		})
+	}
+}
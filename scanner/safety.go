@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SafetyLevel classifica o impacto de um payload no alvo
+type SafetyLevel int
+
+const (
+	// SafetyUnclassified é o valor zero: payloads sem classificação são
+	// tratados como destrutivos
+	SafetyUnclassified SafetyLevel = iota
+	// SafetySafe não altera estado nem acessa recursos sensíveis
+	SafetySafe
+	// SafetyIntrusive pode gravar dados, executar comandos, ler arquivos do
+	// servidor ou alcançar recursos internos, mas não destrói dados
+	SafetyIntrusive
+	// SafetyDestructive pode apagar ou corromper dados
+	SafetyDestructive
+)
+
+var safetyNames = map[SafetyLevel]string{
+	SafetySafe:        "safe",
+	SafetyIntrusive:   "intrusive",
+	SafetyDestructive: "destructive",
+}
+
+func (l SafetyLevel) String() string {
+	if name, ok := safetyNames[l]; ok {
+		return name
+	}
+	return "unclassified"
+}
+
+// ParseSafetyLevel converte o nome do nível (safe, intrusive, destructive)
+func ParseSafetyLevel(name string) (SafetyLevel, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for level, n := range safetyNames {
+		if n == name {
+			return level, nil
+		}
+	}
+	return SafetyUnclassified, fmt.Errorf("nível de segurança inválido: %q (use safe, intrusive ou destructive)", name)
+}
+
+var currentSafety = SafetySafe
+
+// SetSafetyLevel define o nível máximo dos payloads enviados
+func SetSafetyLevel(level SafetyLevel) {
+	currentSafety = level
+}
+
+// CurrentSafetyLevel retorna o nível máximo dos payloads enviados
+func CurrentSafetyLevel() SafetyLevel {
+	return currentSafety
+}
+
+// Allowed informa se um payload do nível indicado pode ser enviado
+func Allowed(level SafetyLevel) bool {
+	if level == SafetyUnclassified {
+		level = SafetyDestructive
+	}
+	return level <= currentSafety
+}
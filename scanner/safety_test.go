@@ -0,0 +1,53 @@
+package scanner
+
+import "testing"
+
+func TestAllowed(t *testing.T) {
+	defer SetSafetyLevel(CurrentSafetyLevel())
+
+	tests := []struct {
+		current SafetyLevel
+		payload SafetyLevel
+		want    bool
+	}{
+		{SafetySafe, SafetySafe, true},
+		{SafetySafe, SafetyIntrusive, false},
+		{SafetyIntrusive, SafetyIntrusive, true},
+		{SafetyIntrusive, SafetyDestructive, false},
+		{SafetyDestructive, SafetyDestructive, true},
+		{SafetyIntrusive, SafetyUnclassified, false},
+		{SafetyDestructive, SafetyUnclassified, true},
+	}
+
+	for _, tt := range tests {
+		SetSafetyLevel(tt.current)
+		if got := Allowed(tt.payload); got != tt.want {
+			t.Errorf("nível %s: Allowed(%s) = %v, want %v", tt.current, tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestParseSafetyLevel(t *testing.T) {
+	for _, level := range []SafetyLevel{SafetySafe, SafetyIntrusive, SafetyDestructive} {
+		got, err := ParseSafetyLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseSafetyLevel(%q) = %v, %v", level.String(), got, err)
+		}
+	}
+	if _, err := ParseSafetyLevel("yolo"); err == nil {
+		t.Error("ParseSafetyLevel aceitou nível inválido")
+	}
+}
+
+// Todo payload embutido precisa de classificação explícita
+func TestPayloadsClassified(t *testing.T) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		t.Fatalf("DefaultLibrary() erro: %v", err)
+	}
+	for _, p := range lib.Payloads {
+		if p.Level() == SafetyUnclassified {
+			t.Errorf("payload %s sem classificação: %q", p.ID, p.Payload)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 )
@@ -13,79 +13,67 @@ import (
 type SQLiPayload struct {
 	Payload     string
 	Description string
-	Type        string // time-based, error-based, union-based, boolean-based
+	Type        string // error-based, boolean-based (UNION é testado em testUnionSQLi)
+	DBMS        DBMS   // Vazio para payloads genéricos
+	Safety      SafetyLevel
 }
 
-// Payloads avançados para detecção de SQL Injection
-var sqliPayloads = []SQLiPayload{
-	// Error-based
-	{Payload: "' OR 1=1--", Description: "OR boolean básico", Type: "boolean"},
-	{Payload: "' OR '1'='1", Description: "OR string boolean", Type: "boolean"},
-	{Payload: "' UNION SELECT null--", Description: "UNION básico", Type: "union"},
-	{Payload: "' UNION SELECT null,null,null--", Description: "UNION 3 colunas", Type: "union"},
-	{Payload: "'; DROP TABLE users--", Description: "DROP TABLE", Type: "error"},
-	{Payload: "' AND 1=0 UNION ALL SELECT 'admin', '81dc9bdb52d04dc20036dbd8313ed055'", Description: "UNION com dados", Type: "union"},
-	{Payload: "admin'--", Description: "Comentário simples", Type: "boolean"},
-	{Payload: "' OR 'x'='x", Description: "OR alternativo", Type: "boolean"},
-	{Payload: "') OR ('1'='1", Description: "OR com parênteses", Type: "boolean"},
-	
-	// Time-based
-	{Payload: "' OR SLEEP(5)--", Description: "MySQL SLEEP", Type: "time"},
-	{Payload: "'; WAITFOR DELAY '0:0:5'--", Description: "SQL Server WAITFOR", Type: "time"},
-	{Payload: "' OR pg_sleep(5)--", Description: "PostgreSQL sleep", Type: "time"},
-	
-	// Stacked queries
-	{Payload: "'; SELECT pg_sleep(5)--", Description: "Stacked query PostgreSQL", Type: "time"},
-	{Payload: "1'; SELECT SLEEP(5)#", Description: "Stacked query MySQL", Type: "time"},
-	
-	// Error-based detailed
-	{Payload: "' AND 1=CONVERT(int, (SELECT @@version))--", Description: "SQL Server version", Type: "error"},
-	{Payload: "' AND extractvalue(1, concat(0x7e, version()))--", Description: "MySQL extractvalue", Type: "error"},
+// payloadsForDialect retorna os payloads SQLi de erro da biblioteca (os que
+// não são modelos de uma técnica) genéricos e os do banco informado (todos,
+// com o banco desconhecido) permitidos no nível de segurança atual
+func payloadsForDialect(dialect DBMS) []SQLiPayload {
+	var payloads []SQLiPayload
+	for _, p := range library.ByCategory(CategorySQLi) {
+		if !p.IsTemplate() && matchesDialect(p, dialect) {
+			payloads = append(payloads, SQLiPayload{
+				Payload:     p.Payload,
+				Description: p.Description,
+				Type:        p.Type,
+				DBMS:        p.DBMS,
+				Safety:      p.Level(),
+			})
+		}
+	}
+	return payloads
+}
+
+// techniquePayloads retorna os modelos da técnica SQLi (SQLiTypeBoolean etc.)
+// genéricos e os do banco informado, permitidos no nível de segurança atual
+func techniquePayloads(technique string, dialect DBMS) []PayloadDef {
+	var payloads []PayloadDef
+	for _, p := range library.ByCategory(CategorySQLi) {
+		if p.Type == technique && matchesDialect(p, dialect) {
+			payloads = append(payloads, p)
+		}
+	}
+	return payloads
 }
 
-// Indicadores expandidos de SQL Injection
-var sqliIndicators = []string{
-	// Erros MySQL
-	"you have an error in your sql syntax",
-	"warning: mysql",
-	"mysql_fetch",
-	"mysql_num_rows",
-	"mysql error",
-	"supplied argument is not a valid mysql",
-	
-	// Erros PostgreSQL
-	"pg_query",
-	"pg_exec",
-	"postgresql",
-	"warning: pg",
-	"pgsql",
-	"unterminated quoted string",
-	
-	// Erros SQL Server
-	"microsoft ole db provider for sql server",
-	"sqlstate",
-	"odbc sql server driver",
-	"microsoft sql native client",
-	"ora-",
-	"sql server",
-	
-	// Erros Oracle
-	"ora-01756",
-	"ora-00933",
-	"oracle error",
-	
-	// Erros SQLite
-	"sqlite_",
-	"sqlite error",
-	"sqliteexception",
-	
-	// Erros genéricos
-	"syntax error",
-	"sql syntax",
-	"database error",
-	"query failed",
-	"unexpected end of sql command",
-	"quoted string not properly terminated",
+// matchesDialect informa se o payload serve para o banco (qualquer um, com o
+// banco desconhecido)
+func matchesDialect(p PayloadDef, dialect DBMS) bool {
+	return dialect == DBMSUnknown || p.DBMS == DBMSUnknown || p.DBMS == dialect
+}
+
+// Nomes das técnicas de SQLi usados nos relatórios
+var sqliTechniques = map[string]string{
+	"error":   "error-based",
+	"boolean": "boolean-based blind",
+	"time":    "time-based blind",
+	"union":   "UNION-based",
+}
+
+// SQLiLabel descreve a injeção com o banco identificado, por exemplo
+// "PostgreSQL error-based SQLi"
+func SQLiLabel(technique string, dbms DBMS) string {
+	label := "SQLi"
+	if name, ok := sqliTechniques[technique]; ok {
+		label = name + " " + label
+	}
+	if dbms != DBMSUnknown {
+		label = string(dbms) + " " + label
+	}
+	return label
 }
 
 // SQLiResult armazena o resultado de um teste SQLi
@@ -97,130 +85,120 @@ type SQLiResult struct {
 	Indicator   string
 	Response    string
 	Field       string
-}
+	DBMS        DBMS // Banco identificado, quando conhecido
 
-// TestSQLi testa vulnerabilidades SQL Injection em um formulário
-func TestSQLi(form Form, baseURL string, client *http.Client) bool {
-	for _, sqliPayload := range sqliPayloads {
-		if testSingleSQLi(form, baseURL, client, sqliPayload.Payload) {
-			return true
-		}
-		time.Sleep(50 * time.Millisecond) // Rate limiting
-	}
-	return false
+	// Evidência do blind boolean: respostas das condições verdadeira e falsa
+	TrueFingerprint  *ResponseFingerprint
+	FalseFingerprint *ResponseFingerprint
+
+	// Evidência do time-based: latência normal e tempos por atraso pedido
+	Baseline time.Duration
+	Timings  []TimingSample
+
+	// Evidência do UNION: número de colunas e colunas que exibem o marcador
+	Columns          int
+	ReflectedColumns []int
 }
 
-// TestSQLiDetailed testa SQLi e retorna resultados detalhados
-func TestSQLiDetailed(form Form, baseURL string, client *http.Client) []SQLiResult {
+// TestSQLiDetailed testa SQLi e retorna resultados detalhados. Para cada
+// campo são testados os payloads de erro e, se nada for encontrado, UNION com
+// marcador, pares de condições verdadeira/falsa (blind boolean) e atrasos
+// (blind time-based).
+// Depois da primeira injeção o banco é identificado e os campos seguintes
+// recebem apenas payloads genéricos e do dialeto encontrado.
+func TestSQLiDetailed(ctx context.Context, form Form, baseURL string, client *http.Client) []SQLiResult {
 	var results []SQLiResult
+	dialect := DBMSUnknown
 
-	for _, sqliPayload := range sqliPayloads {
-		for _, field := range form.Inputs {
-			data := url.Values{}
-			// Preenche outros campos com valores normais
-			for _, input := range form.Inputs {
-				if input == field {
-					data.Set(input, sqliPayload.Payload)
-				} else {
-					data.Set(input, "test")
-				}
-			}
-
-			target := baseURL + form.Action
-			var res *http.Response
-			var err error
+	for _, input := range form.Inputs {
+		if ctx.Err() != nil {
+			break
+		}
+		field := input.Name
+		var finding *SQLiResult
 
-			startTime := time.Now()
-			if form.Method == "POST" {
-				res, err = client.PostForm(target, data)
-			} else {
-				fullURL := target + "?" + data.Encode()
-				res, err = client.Get(fullURL)
+		for _, sqliPayload := range payloadsForDialect(dialect) {
+			if ctx.Err() != nil {
+				break
 			}
-			elapsed := time.Since(startTime)
-
-			if err != nil {
+			result, ok := testErrorSQLi(ctx, form, baseURL, field, sqliPayload, client)
+			if !ok {
 				continue
 			}
+			results = append(results, result)
 
-			buf := new(strings.Builder)
-			io.Copy(buf, res.Body)
-			body := buf.String()
-			res.Body.Close()
-
-			vulnerable, indicator := detectSQLi(body, sqliPayload.Type, elapsed)
-
-			results = append(results, SQLiResult{
-				Vulnerable:  vulnerable,
-				Payload:     sqliPayload.Payload,
-				Description: sqliPayload.Description,
-				Type:        sqliPayload.Type,
-				Indicator:   indicator,
-				Response:    truncateString(body, 500),
-				Field:       field,
-			})
-
-			if vulnerable {
+			if result.Vulnerable {
+				finding = &results[len(results)-1]
 				break // Vulnerável encontrado
 			}
+		}
 
-			time.Sleep(50 * time.Millisecond)
+		if finding == nil {
+			if result, ok := testUnionSQLi(ctx, form, baseURL, input, dialect, client); ok {
+				results = append(results, result)
+				finding = &results[len(results)-1]
+			}
 		}
-	}
 
-	return results
-}
+		if finding == nil {
+			if result, ok := testBooleanSQLi(ctx, form, baseURL, input, client); ok {
+				results = append(results, result)
+				finding = &results[len(results)-1]
+			}
+		}
 
-func testSingleSQLi(form Form, baseURL string, client *http.Client, payload string) bool {
-	data := url.Values{}
+		if finding == nil {
+			if result, ok := testTimeSQLi(ctx, form, baseURL, input, dialect, client); ok {
+				results = append(results, result)
+				finding = &results[len(results)-1]
+			}
+		}
 
-	for _, input := range form.Inputs {
-		data.Set(input, payload)
+		if finding == nil {
+			continue
+		}
+
+		if dialect == DBMSUnknown {
+			// Sondas específicas confirmam (ou corrigem) o banco indicado pelo erro
+			if dbms := confirmDBMS(ctx, form, baseURL, input, finding.DBMS, client); dbms != DBMSUnknown {
+				finding.DBMS = dbms
+			}
+			dialect = finding.DBMS
+		} else if finding.DBMS == DBMSUnknown {
+			finding.DBMS = dialect
+		}
 	}
 
-	target := baseURL + form.Action
-	var res *http.Response
-	var err error
+	return results
+}
 
-	startTime := time.Now()
-	if form.Method == "POST" {
-		res, err = client.PostForm(target, data)
-	} else {
-		fullURL := target + "?" + data.Encode()
-		res, err = client.Get(fullURL)
-	}
-	elapsed := time.Since(startTime)
+// testErrorSQLi envia um payload no campo, com os outros campos preenchidos
+// com valores válidos, e procura mensagens de erro de banco na resposta. ok é
+// false se a requisição falhou.
+func testErrorSQLi(ctx context.Context, form Form, baseURL, field string, p SQLiPayload, client *http.Client) (result SQLiResult, ok bool) {
+	data := buildTestData(form, field, p.Payload)
 
+	res, err := sendRequest(ctx, form, baseURL, data, client)
 	if err != nil {
-		return false
+		return SQLiResult{}, false
 	}
-	defer res.Body.Close()
 
 	buf := new(strings.Builder)
 	io.Copy(buf, res.Body)
 	body := buf.String()
-
-	vulnerable, _ := detectSQLi(body, "error", elapsed)
-	return vulnerable
-}
-
-// detectSQLi verifica se há indicadores de SQL Injection
-func detectSQLi(body, payloadType string, elapsed time.Duration) (bool, string) {
-	bodyLower := strings.ToLower(body)
-
-	// Time-based detection
-	if payloadType == "time" && elapsed > 4*time.Second {
-		return true, fmt.Sprintf("Time delay detected: %v", elapsed)
-	}
-
-	// Error-based detection
-	for _, indicator := range sqliIndicators {
-		if strings.Contains(bodyLower, indicator) {
-			return true, indicator
-		}
-	}
-
-	return false, ""
+	res.Body.Close()
+
+	dbms, indicator, found := matchSQLError(body)
+	return SQLiResult{
+		Vulnerable:  found,
+		Payload:     p.Payload,
+		Description: p.Description,
+		Type:        p.Type,
+		Indicator:   indicator,
+		Response:    truncateString(body, 500),
+		Field:       field,
+		DBMS:        dbms,
+	}, true
 }
 
 func truncateString(s string, maxLen int) string {
@@ -0,0 +1,214 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ResponseFingerprint resume uma resposta para comparação entre condições
+type ResponseFingerprint struct {
+	Status     int
+	Length     int     // Tamanho do corpo normalizado, sem o valor enviado
+	Similarity float64 // Similaridade do corpo normalizado com o baseline (0 a 1)
+}
+
+func (f ResponseFingerprint) String() string {
+	return fmt.Sprintf("status %d, %d bytes, similaridade %.2f", f.Status, f.Length, f.Similarity)
+}
+
+const (
+	// booleanRounds é o número de pares consistentes exigidos para reportar
+	booleanRounds = 3
+	// booleanMargin é a queda mínima de similaridade da condição falsa em
+	// relação à variação natural da página
+	booleanMargin = 0.1
+)
+
+// booleanBaseline resume as duas respostas sem injeção: o status, o tamanho
+// e a variação natural da página entre elas
+type booleanBaseline struct {
+	status       int
+	length       int
+	stability    float64 // Similaridade entre as duas respostas
+	lengthJitter int     // Diferença de tamanho entre as duas respostas
+}
+
+// booleanResponse é uma resposta lida para comparação
+type booleanResponse struct {
+	status int
+	body   string
+}
+
+// testBooleanSQLi envia pares de condições verdadeira/falsa no campo, a
+// partir dos modelos boolean da biblioteca, e compara as respostas com o
+// baseline. Cada rodada usa números diferentes, para que as repetições não
+// sejam respondidas por cache. Só reporta quando todas as rodadas mostram a
+// condição verdadeira igual ao baseline e a falsa diferente.
+func testBooleanSQLi(ctx context.Context, form Form, baseURL string, input Input, client *http.Client) (SQLiResult, bool) {
+	prefix := baselineValue(input)
+
+	fetch := func(value string) (booleanResponse, error) {
+		return fetchField(ctx, form, baseURL, input.Name, value, client)
+	}
+
+	// Duas respostas de baseline medem a variação natural da página
+	base1, err := fetch(prefix)
+	if err != nil {
+		return SQLiResult{}, false
+	}
+	base2, err := fetch(prefix)
+	if err != nil || base1.status != base2.status {
+		return SQLiResult{}, false
+	}
+	baseline := normalizeBody(base1.body, prefix)
+	base2Body := normalizeBody(base2.body, prefix)
+	base := booleanBaseline{
+		status:       base1.status,
+		length:       len(baseline),
+		stability:    similarity(baseline, base2Body),
+		lengthJitter: abs(len(baseline) - len(base2Body)),
+	}
+
+	for _, pair := range techniquePayloads(SQLiTypeBoolean, DBMSUnknown) {
+		var trueFP, falseFP ResponseFingerprint
+		var truePayload, falsePayload string
+		confirmed := true
+
+		for round := 0; round < booleanRounds; round++ {
+			n := 1 + round*7
+			truePayload = prefix + fmt.Sprintf(pair.Payload, n, n)
+			falsePayload = prefix + fmt.Sprintf(pair.Payload, n, n+1)
+
+			trueResp, err := fetch(truePayload)
+			if err != nil {
+				confirmed = false
+				break
+			}
+			falseResp, err := fetch(falsePayload)
+			if err != nil {
+				confirmed = false
+				break
+			}
+
+			trueFP = fingerprint(trueResp, baseline, truePayload)
+			falseFP = fingerprint(falseResp, baseline, falsePayload)
+
+			if !booleanDiffers(base, trueFP, falseFP) {
+				confirmed = false
+				break
+			}
+		}
+
+		if !confirmed {
+			continue
+		}
+
+		return SQLiResult{
+			Vulnerable:       true,
+			Payload:          truePayload + " | " + falsePayload,
+			Description:      "Blind boolean (" + pair.Description + ")",
+			Type:             "boolean",
+			Indicator:        fmt.Sprintf("verdadeira: %s; falsa: %s", trueFP, falseFP),
+			Field:            input.Name,
+			TrueFingerprint:  &trueFP,
+			FalseFingerprint: &falseFP,
+		}, true
+	}
+
+	return SQLiResult{}, false
+}
+
+// fetchField envia o valor no campo e lê a resposta para comparação
+func fetchField(ctx context.Context, form Form, baseURL, field, value string, client *http.Client) (booleanResponse, error) {
+	res, err := sendRequest(ctx, form, baseURL, buildTestData(form, field, value), client)
+	if err != nil {
+		return booleanResponse{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 5<<20))
+	if err != nil {
+		return booleanResponse{}, err
+	}
+	return booleanResponse{status: res.StatusCode, body: string(body)}, nil
+}
+
+// booleanDiffers verifica se a condição verdadeira reproduz o baseline e a
+// falsa não, comparando status, tamanho e similaridade. A verdadeira é
+// comparada com o baseline e a falsa com a verdadeira; o tamanho pode variar
+// o quanto variou entre as duas respostas de baseline mais booleanMargin do
+// tamanho da página.
+func booleanDiffers(base booleanBaseline, trueFP, falseFP ResponseFingerprint) bool {
+	threshold := base.stability - booleanMargin
+	lengthTolerance := base.lengthJitter + int(booleanMargin*float64(base.length))
+
+	trueMatches := trueFP.Status == base.status && trueFP.Similarity >= threshold &&
+		abs(trueFP.Length-base.length) <= lengthTolerance
+	falseDiffers := falseFP.Status != trueFP.Status || falseFP.Similarity < threshold ||
+		abs(falseFP.Length-trueFP.Length) > lengthTolerance
+
+	return trueMatches && falseDiffers
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// fingerprint calcula a impressão da resposta em relação ao baseline
+func fingerprint(resp booleanResponse, baseline, payload string) ResponseFingerprint {
+	body := normalizeBody(resp.body, payload)
+	return ResponseFingerprint{
+		Status:     resp.status,
+		Length:     len(body),
+		Similarity: similarity(baseline, body),
+	}
+}
+
+var (
+	digitsRe     = regexp.MustCompile(`[0-9]+`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+// normalizeBody remove o valor enviado (cru e com encoding HTML/URL) e
+// elementos voláteis, como números, tokens e datas, antes da comparação
+func normalizeBody(body, sent string) string {
+	body = strings.ToLower(body)
+	for _, v := range []string{sent, html.EscapeString(sent), url.QueryEscape(sent)} {
+		if v != "" {
+			body = strings.ReplaceAll(body, strings.ToLower(v), "")
+		}
+	}
+	body = digitsRe.ReplaceAllString(body, "0")
+	return whitespaceRe.ReplaceAllString(body, " ")
+}
+
+// similarity calcula a semelhança entre dois textos pelo coeficiente de Dice
+// sobre o multiconjunto de palavras
+func similarity(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	counts := make(map[string]int, len(wordsA))
+	for _, w := range wordsA {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range wordsB {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
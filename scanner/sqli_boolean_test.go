@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const productList = "<ul><li>Caneta azul</li><li>Caderno 96 folhas</li><li>Lápis preto</li><li>Borracha branca</li></ul>"
+
+var conditionRe = regexp.MustCompile(`^test' AND '(\d+)'='(\d+)$`)
+
+// blindHandler simula uma busca que concatena o valor em uma query SQL mas
+// nunca exibe erros do banco
+func blindHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	fmt.Fprint(w, "<html><body><h1>Produtos</h1>")
+	switch m := conditionRe.FindStringSubmatch(q); {
+	case q == "test":
+		fmt.Fprint(w, productList)
+	case m != nil && m[1] == m[2]:
+		fmt.Fprint(w, productList)
+	case m != nil:
+		fmt.Fprint(w, "<p>Nenhum produto encontrado</p>")
+	case strings.Contains(q, "'"):
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "<p>Erro interno</p>")
+	default:
+		fmt.Fprint(w, "<p>Nenhum produto encontrado</p>")
+	}
+	fmt.Fprint(w, "</body></html>")
+}
+
+// lengthHandler simula uma busca cujas respostas só diferem no tamanho: um
+// bloco de dados de uma única palavra, que quase não pesa na similaridade.
+// trueBlob e falseBlob são os blocos das condições verdadeira e falsa; o
+// valor sem injeção recebe o bloco cheio.
+func lengthHandler(trueBlob, falseBlob string) http.HandlerFunc {
+	full := strings.Repeat("a", 3000)
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		blob := full
+		if m := conditionRe.FindStringSubmatch(q); m != nil {
+			blob = falseBlob
+			if m[1] == m[2] {
+				blob = trueBlob
+			}
+		} else if q != "test" {
+			blob = ""
+		}
+		fmt.Fprint(w, "<html><body><nav>"+strings.Repeat("Início Produtos Categorias Ofertas Carrinho Conta Ajuda Contato ", 6)+"</nav>")
+		fmt.Fprint(w, productList+"<p>"+blob+"</p></body></html>")
+	}
+}
+
+func TestBooleanSQLi(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantVuln   bool
+		lengthOnly bool // Respostas diferem no tamanho, não na similaridade
+	}{
+		{
+			name:     "Condição falsa remove os resultados",
+			handler:  blindHandler,
+			wantVuln: true,
+		},
+		{
+			name: "Valor apenas refletido",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "<p>Resultados para %s</p>%s", html.EscapeString(r.URL.Query().Get("q")), productList)
+			},
+			wantVuln: false,
+		},
+		{
+			name:       "Condição falsa muda só o tamanho",
+			handler:    lengthHandler(strings.Repeat("a", 3000), "b"),
+			wantVuln:   true,
+			lengthOnly: true,
+		},
+		{
+			name:     "Condição verdadeira muda só o tamanho",
+			handler:  lengthHandler("b", strings.Repeat("erro ", 60)),
+			wantVuln: false,
+		},
+		{
+			name: "Conteúdo dinâmico sem injeção",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "<p>token %s</p>%s", newCanary(), productList)
+			},
+			wantVuln: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+			result, ok := testBooleanSQLi(context.Background(), form, server.URL+"/busca", form.Inputs[0], server.Client())
+
+			if ok != tt.wantVuln {
+				t.Fatalf("vulnerável = %v, want %v (%+v)", ok, tt.wantVuln, result)
+			}
+			if !ok {
+				return
+			}
+			if result.TrueFingerprint == nil || result.FalseFingerprint == nil {
+				t.Fatal("fingerprints ausentes no resultado")
+			}
+			falseDiffers := result.FalseFingerprint.Similarity <= 0.9
+			if tt.lengthOnly {
+				falseDiffers = result.FalseFingerprint.Length < result.TrueFingerprint.Length/2
+			}
+			if result.TrueFingerprint.Similarity < 0.99 || !falseDiffers {
+				t.Errorf("fingerprints inesperados: verdadeira %s, falsa %s",
+					result.TrueFingerprint, result.FalseFingerprint)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"a b c", "a b c", 1},
+		{"a b c d", "a b x y", 0.5},
+		{"", "", 1},
+		{"a", "", 0},
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Atrasos pedidos em cada série, em unidades de sqliTimeUnit
+var timeDelays = []int{0, 3, 6}
+
+const (
+	// timeBaselineSamples é o número de requisições usadas para medir a
+	// latência normal do endpoint
+	timeBaselineSamples = 3
+	// timeRetries é o número de séries consistentes exigidas para reportar
+	timeRetries = 2
+)
+
+// sqliTimeUnit é a duração de uma unidade de atraso (1 segundo nos bancos);
+// os testes reduzem esse valor
+var sqliTimeUnit = time.Second
+
+// TimingSample é uma medição do tempo de resposta para um atraso pedido
+type TimingSample struct {
+	Delay   int // Atraso pedido, em segundos
+	Elapsed time.Duration
+}
+
+// testTimeSQLi mede a latência normal do endpoint e envia atrasos de
+// tamanhos diferentes. Só reporta quando o tempo de resposta cresce
+// linearmente com o atraso pedido em todas as séries. Os payloads são os
+// modelos time da biblioteca (%d é o número de segundos pedido ao banco); com
+// o dialeto conhecido, apenas os desse banco são enviados.
+func testTimeSQLi(ctx context.Context, form Form, baseURL string, input Input, dialect DBMS, client *http.Client) (SQLiResult, bool) {
+	measure := timeMeasurer(ctx, form, baseURL, input, client)
+	baseline, jitter, ok := measureBaseline(measure, input)
+	if !ok {
+		return SQLiResult{}, false
+	}
+
+	for _, tp := range techniquePayloads(SQLiTypeTime, dialect) {
+		if result, ok := timeSeries(measure, input, tp, baseline, jitter); ok {
+			return result, true
+		}
+	}
+
+	return SQLiResult{}, false
+}
+
+// testTimePayload testa um único payload de atraso, com a própria medição
+// da latência normal. É a unidade de trabalho time-based de ScanForms.
+func testTimePayload(ctx context.Context, form Form, baseURL string, input Input, tp PayloadDef, client *http.Client) (SQLiResult, bool) {
+	measure := timeMeasurer(ctx, form, baseURL, input, client)
+	baseline, jitter, ok := measureBaseline(measure, input)
+	if !ok {
+		return SQLiResult{}, false
+	}
+	return timeSeries(measure, input, tp, baseline, jitter)
+}
+
+// timeMeasurer retorna uma função que envia o valor no campo e mede o tempo
+// de resposta
+func timeMeasurer(ctx context.Context, form Form, baseURL string, input Input, client *http.Client) func(string) (time.Duration, error) {
+	return func(value string) (time.Duration, error) {
+		traced, elapsed := startTimer(ctx)
+		res, err := sendRequest(traced, form, baseURL, buildTestData(form, input.Name, value), client)
+		if err != nil {
+			return 0, err
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		return elapsed(), nil
+	}
+}
+
+// measureBaseline mede a latência normal do endpoint e a sua variação
+func measureBaseline(measure func(string) (time.Duration, error), input Input) (time.Duration, time.Duration, bool) {
+	var baselines []time.Duration
+	for i := 0; i < timeBaselineSamples; i++ {
+		elapsed, err := measure(baselineValue(input))
+		if err != nil {
+			return 0, 0, false
+		}
+		baselines = append(baselines, elapsed)
+	}
+	baseline, jitter := meanAndSpread(baselines)
+	return baseline, jitter, true
+}
+
+// timeSeries envia as séries de atrasos do payload e confirma a injeção se
+// todas crescem linearmente com o atraso pedido
+func timeSeries(measure func(string) (time.Duration, error), input Input, tp PayloadDef, baseline, jitter time.Duration) (SQLiResult, bool) {
+	var timings []TimingSample
+	for retry := 0; retry < timeRetries; retry++ {
+		var series []TimingSample
+		for _, delay := range timeDelays {
+			elapsed, err := measure(fmt.Sprintf(tp.Payload, delay))
+			if err != nil {
+				return SQLiResult{}, false
+			}
+			series = append(series, TimingSample{Delay: delay, Elapsed: elapsed})
+
+			// Interrompe a série assim que um ponto foge da reta
+			if !withinTolerance(series[len(series)-1], baseline, jitter) {
+				return SQLiResult{}, false
+			}
+		}
+		timings = append(timings, series...)
+
+		if !linearDelay(series, baseline) {
+			return SQLiResult{}, false
+		}
+	}
+
+	return SQLiResult{
+		Vulnerable:  true,
+		Payload:     fmt.Sprintf(tp.Payload, timeDelays[len(timeDelays)-1]),
+		Description: "Blind time-based (" + tp.Description + ")",
+		Type:        "time",
+		Indicator:   fmt.Sprintf("baseline %v; %s", baseline.Round(time.Millisecond), formatTimings(timings)),
+		Field:       input.Name,
+		DBMS:        tp.DBMS,
+		Baseline:    baseline,
+		Timings:     timings,
+	}, true
+}
+
+// withinTolerance verifica se o tempo medido corresponde ao baseline mais o
+// atraso pedido, com folga de meia unidade mais a variação do baseline
+func withinTolerance(s TimingSample, baseline, jitter time.Duration) bool {
+	expected := baseline + time.Duration(s.Delay)*sqliTimeUnit
+	tolerance := sqliTimeUnit/2 + jitter
+	diff := s.Elapsed - expected
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance
+}
+
+// linearDelay ajusta uma reta (mínimos quadrados) aos tempos medidos e exige
+// inclinação próxima de uma unidade de tempo por unidade de atraso
+func linearDelay(series []TimingSample, baseline time.Duration) bool {
+	if len(series) < 2 {
+		return false
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range series {
+		x := float64(s.Delay)
+		y := float64(s.Elapsed-baseline) / float64(sqliTimeUnit)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(series))
+	den := n*sumXX - sumX*sumX
+	if den == 0 {
+		return false
+	}
+	slope := (n*sumXY - sumX*sumY) / den
+
+	return slope >= 0.75 && slope <= 1.25
+}
+
+// meanAndSpread retorna a média e a diferença entre o maior e o menor valor
+func meanAndSpread(values []time.Duration) (time.Duration, time.Duration) {
+	var sum, min, max time.Duration
+	for i, v := range values {
+		sum += v
+		if i == 0 || v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return sum / time.Duration(len(values)), max - min
+}
+
+func formatTimings(timings []TimingSample) string {
+	parts := make([]string, 0, len(timings))
+	for _, t := range timings {
+		parts = append(parts, fmt.Sprintf("%ds=%v", t.Delay, t.Elapsed.Round(time.Millisecond)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTimeSQLi(t *testing.T) {
+	// Reduz a unidade de atraso para o teste não levar segundos
+	defer func(unit time.Duration) { sqliTimeUnit = unit }(sqliTimeUnit)
+	sqliTimeUnit = 100 * time.Millisecond
+
+	sleepRe := regexp.MustCompile(`^' AND SLEEP\((\d+)\)-- $`)
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		wantVuln bool
+	}{
+		{
+			name: "Atraso proporcional ao SLEEP",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if m := sleepRe.FindStringSubmatch(r.URL.Query().Get("q")); m != nil {
+					n, _ := strconv.Atoi(m[1])
+					time.Sleep(time.Duration(n) * sqliTimeUnit)
+				}
+				fmt.Fprint(w, "ok")
+			},
+			wantVuln: true,
+		},
+		{
+			name: "Servidor lento sem injeção",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(4 * sqliTimeUnit)
+				fmt.Fprint(w, "ok")
+			},
+			wantVuln: false,
+		},
+		{
+			name: "Atraso fixo apenas com payloads",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if sleepRe.MatchString(r.URL.Query().Get("q")) {
+					time.Sleep(5 * sqliTimeUnit)
+				}
+				fmt.Fprint(w, "ok")
+			},
+			wantVuln: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+			result, ok := testTimeSQLi(context.Background(), form, server.URL+"/busca", form.Inputs[0], DBMSUnknown, server.Client())
+
+			if ok != tt.wantVuln {
+				t.Fatalf("vulnerável = %v, want %v (%+v)", ok, tt.wantVuln, result)
+			}
+			if ok && len(result.Timings) != len(timeDelays)*timeRetries {
+				t.Errorf("Timings = %v, want %d medições", result.Timings, len(timeDelays)*timeRetries)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// unionMaxColumns limita a busca do número de colunas com ORDER BY
+	unionMaxColumns = 20
+	// unionMaxNullColumns limita a busca com UNION SELECT NULL,...
+	unionMaxNullColumns = 10
+)
+
+// unionContext descreve como fechar o valor original e comentar o resto da
+// query em um contexto de injeção
+type unionContext struct {
+	Break       string
+	Comment     string
+	Description string
+}
+
+// unionContexts retorna os modelos union da biblioteca para o banco, com o
+// fechamento do valor e o comentário separados em volta de %s
+func unionContexts(dialect DBMS) []unionContext {
+	var contexts []unionContext
+	for _, p := range techniquePayloads(SQLiTypeUnion, dialect) {
+		brk, comment, _ := strings.Cut(p.Payload, "%s")
+		contexts = append(contexts, unionContext{Break: brk, Comment: comment, Description: p.Description})
+	}
+	return contexts
+}
+
+// Concatenação por banco. O marcador é enviado em duas partes e só aparece
+// inteiro na resposta se o banco executar a concatenação, o que descarta a
+// simples reflexão do valor enviado.
+var concatStyles = map[DBMS][]string{
+	DBMSMySQL:      {"CONCAT(%s,%s)"},
+	DBMSPostgreSQL: {"%s||%s"},
+	DBMSMSSQL:      {"%s+%s"},
+	DBMSOracle:     {"%s||%s"},
+	DBMSSQLite:     {"%s||%s"},
+	DBMSUnknown:    {"CONCAT(%s,%s)", "%s||%s", "%s+%s"},
+}
+
+// testUnionSQLi descobre o número de colunas da query com ORDER BY n (ou
+// UNION SELECT NULL,... quando os erros do banco aparecem na resposta) e
+// injeta um marcador literal em cada coluna. A injeção só é confirmada
+// quando o marcador volta na resposta. Apenas literais e NULL são
+// selecionados; nenhum dado de tabela é lido.
+func testUnionSQLi(ctx context.Context, form Form, baseURL string, input Input, dialect DBMS, client *http.Client) (SQLiResult, bool) {
+	for _, uc := range unionContexts(dialect) {
+		prefix := baselineValue(input)
+		if uc.Break == "" {
+			prefix = "1"
+		}
+
+		columns, ok := unionColumnCount(ctx, form, baseURL, input.Name, prefix, uc, client)
+		if !ok {
+			columns, ok = unionNullCount(ctx, form, baseURL, input.Name, prefix, uc, dialect, client)
+		}
+		if !ok {
+			continue
+		}
+
+		reflected, payload := unionReflectedColumns(ctx, form, baseURL, input.Name, prefix, uc, columns, dialect, client)
+		if len(reflected) == 0 {
+			continue
+		}
+
+		names := make([]string, len(reflected))
+		for i, col := range reflected {
+			names[i] = strconv.Itoa(col)
+		}
+
+		return SQLiResult{
+			Vulnerable:       true,
+			Payload:          payload,
+			Description:      fmt.Sprintf("UNION com %d coluna(s) (%s)", columns, uc.Description),
+			Type:             "union",
+			Indicator:        "marcador refletido na(s) coluna(s) " + strings.Join(names, ", "),
+			Field:            input.Name,
+			Columns:          columns,
+			ReflectedColumns: reflected,
+		}, true
+	}
+
+	return SQLiResult{}, false
+}
+
+// unionColumnCount aumenta n em ORDER BY n até a resposta mudar. Retorna
+// false se ORDER BY não tiver efeito observável no contexto.
+func unionColumnCount(ctx context.Context, form Form, baseURL, field, prefix string, uc unionContext, client *http.Client) (int, bool) {
+	orderBy := func(n int) string {
+		return fmt.Sprintf("%s%s ORDER BY %d%s", prefix, uc.Break, n, uc.Comment)
+	}
+
+	ref, err := fetchField(ctx, form, baseURL, field, orderBy(1), client)
+	if err != nil || ref.status >= 400 {
+		return 0, false
+	}
+	if _, _, found := matchSQLError(ref.body); found {
+		return 0, false
+	}
+
+	// Uma coluna inexistente precisa mudar a resposta, senão não há sinal
+	if !responseChanged(ctx, ref, orderBy(1), form, baseURL, field, orderBy(1000), client) {
+		return 0, false
+	}
+
+	for n := 2; n <= unionMaxColumns; n++ {
+		if responseChanged(ctx, ref, orderBy(1), form, baseURL, field, orderBy(n), client) {
+			return n - 1, true
+		}
+	}
+	return 0, false
+}
+
+// responseChanged envia o valor e compara a resposta com a de referência
+func responseChanged(ctx context.Context, ref booleanResponse, refSent string, form Form, baseURL, field, value string, client *http.Client) bool {
+	resp, err := fetchField(ctx, form, baseURL, field, value, client)
+	if err != nil {
+		return true
+	}
+	if resp.status != ref.status {
+		return true
+	}
+	if _, _, found := matchSQLError(resp.body); found {
+		return true
+	}
+	fp := fingerprint(resp, normalizeBody(ref.body, refSent), value)
+	return fp.Similarity < 1-booleanMargin
+}
+
+// unionNullCount testa UNION SELECT NULL,... com quantidades crescentes de
+// colunas. Só é usado quando o valor quebrado gera erro visível do banco, já
+// que o sinal é o desaparecimento desse erro.
+func unionNullCount(ctx context.Context, form Form, baseURL, field, prefix string, uc unionContext, dialect DBMS, client *http.Client) (int, bool) {
+	broken, err := fetchField(ctx, form, baseURL, field, prefix+"'", client)
+	if err != nil {
+		return 0, false
+	}
+	if _, _, found := matchSQLError(broken.body); !found {
+		return 0, false
+	}
+
+	for n := 1; n <= unionMaxNullColumns; n++ {
+		value := unionSelect(prefix, uc, make([]string, n), dialect)
+		resp, err := fetchField(ctx, form, baseURL, field, value, client)
+		if err != nil {
+			continue
+		}
+		if _, _, found := matchSQLError(resp.body); !found && resp.status < 400 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// unionReflectedColumns injeta o marcador concatenado em cada coluna e
+// retorna as colunas em que ele aparece na resposta, com o primeiro payload
+// confirmado
+func unionReflectedColumns(ctx context.Context, form Form, baseURL, field, prefix string, uc unionContext, columns int, dialect DBMS, client *http.Client) ([]int, string) {
+	styles := concatStyles[dialect]
+	var reflected []int
+	firstPayload := ""
+
+	for col := 1; col <= columns; col++ {
+		for i, style := range styles {
+			marker := newCanary()
+			exprs := make([]string, columns)
+			exprs[col-1] = fmt.Sprintf(style, "'"+marker[:6]+"'", "'"+marker[6:]+"'")
+			value := unionSelect(prefix, uc, exprs, dialect)
+
+			resp, err := fetchField(ctx, form, baseURL, field, value, client)
+			if err != nil || !strings.Contains(resp.body, marker) {
+				continue
+			}
+
+			reflected = append(reflected, col)
+			if firstPayload == "" {
+				firstPayload = value
+			}
+			// Mantém a concatenação que funcionou para as próximas colunas
+			styles = []string{styles[i]}
+			break
+		}
+	}
+
+	return reflected, firstPayload
+}
+
+// unionSelect monta o valor com UNION ALL SELECT. A condição falsa antes do
+// UNION faz a linha injetada ser a única do resultado. Colunas vazias são NULL.
+func unionSelect(prefix string, uc unionContext, exprs []string, dialect DBMS) string {
+	cols := make([]string, len(exprs))
+	for i, expr := range exprs {
+		if expr == "" {
+			expr = "NULL"
+		}
+		cols[i] = expr
+	}
+
+	query := prefix + uc.Break + " AND 1=2 UNION ALL SELECT " + strings.Join(cols, ",")
+	if dialect == DBMSOracle {
+		query += " FROM DUAL"
+	}
+	return query + uc.Comment
+}
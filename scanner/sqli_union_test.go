@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var (
+	orderByRe = regexp.MustCompile(`^test' ORDER BY (\d+)-- $`)
+	unionRe   = regexp.MustCompile(`^test' AND 1=2 UNION ALL SELECT (.*)-- $`)
+	columnRe  = regexp.MustCompile(`CONCAT\('([^']*)','([^']*)'\)|NULL`)
+)
+
+// unionHandler simula uma query de 3 colunas em MySQL que exibe a segunda
+func unionHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	fmt.Fprint(w, "<h1>Produtos</h1>")
+
+	if m := orderByRe.FindStringSubmatch(q); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n > 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<p>Erro interno</p>")
+			return
+		}
+		fmt.Fprint(w, productList)
+		return
+	}
+
+	if m := unionRe.FindStringSubmatch(q); m != nil {
+		cols := columnRe.FindAllStringSubmatch(m[1], -1)
+		if len(cols) != 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<p>Erro interno</p>")
+			return
+		}
+		fmt.Fprintf(w, "<ul><li>%s</li></ul>", cols[1][1]+cols[1][2])
+		return
+	}
+
+	if q == "test" {
+		fmt.Fprint(w, productList)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	fmt.Fprint(w, "<p>Erro interno</p>")
+}
+
+func TestUnionSQLi(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		wantVuln      bool
+		wantColumns   int
+		wantReflected []int
+	}{
+		{
+			name:          "Três colunas com a segunda exibida",
+			handler:       unionHandler,
+			wantVuln:      true,
+			wantColumns:   3,
+			wantReflected: []int{2},
+		},
+		{
+			name: "Valor refletido sem encoding não confirma",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "<p>Resultados para %s</p>", r.URL.Query().Get("q"))
+			},
+			wantVuln: false,
+		},
+		{
+			name: "Valor refletido com encoding",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "<p>Resultados para %s</p>%s", html.EscapeString(r.URL.Query().Get("q")), productList)
+			},
+			wantVuln: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			form := Form{Method: "GET", Inputs: []Input{{Name: "q", Type: "text"}}}
+			result, ok := testUnionSQLi(context.Background(), form, server.URL+"/busca", form.Inputs[0], DBMSUnknown, server.Client())
+
+			if ok != tt.wantVuln {
+				t.Fatalf("vulnerável = %v, want %v (%+v)", ok, tt.wantVuln, result)
+			}
+			if !ok {
+				return
+			}
+			if result.Columns != tt.wantColumns {
+				t.Errorf("Columns = %d, want %d", result.Columns, tt.wantColumns)
+			}
+			if fmt.Sprint(result.ReflectedColumns) != fmt.Sprint(tt.wantReflected) {
+				t.Errorf("ReflectedColumns = %v, want %v", result.ReflectedColumns, tt.wantReflected)
+			}
+			if strings.Contains(strings.ToUpper(result.Payload), " FROM ") {
+				t.Errorf("payload lê tabela: %q", result.Payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"furador-de-coco/logger"
+)
+
+// storedXSSTemplate fecha os contextos mais comuns (atributo, comentário,
+// textarea, title, style e script) antes de injetar o elemento marcado, já
+// que no momento do envio não se sabe onde o valor será exibido
+const storedXSSTemplate = `'"--></textarea></title></style></script><img src=x onerror=alert(1) data-%s>`
+
+// StoredXSSResult representa um payload armazenado que reapareceu sem encoding
+type StoredXSSResult struct {
+	Field      string
+	Payload    string
+	FormAction string
+	FormMethod string
+	SubmitURL  string // Página do formulário que recebeu o payload
+	DisplayURL string // Página onde o payload foi exibido
+}
+
+// storedInjection registra qual formulário e campo receberam cada token
+type storedInjection struct {
+	form    Form
+	field   string
+	payload string
+	submit  string
+}
+
+// TestStoredXSS envia payloads com tokens únicos para todos os campos de
+// todos os formulários e depois revisita as páginas de exibição. Cada token
+// encontrado sem encoding é ligado de volta ao formulário e campo de origem.
+// Como grava dados na aplicação, exige o nível de segurança intrusive.
+func TestStoredXSS(ctx context.Context, forms []Form, baseURL string, displayURLs []string, client *http.Client) []StoredXSSResult {
+	if !Allowed(SafetyIntrusive) {
+		logger.Warn("Teste de XSS armazenado ignorado: grava dados no alvo e requer -safety intrusive")
+		return nil
+	}
+
+	injections := make(map[string]storedInjection)
+	var tokens []string // Ordem de envio, para resultados estáveis
+
+	for _, form := range forms {
+		submitURL, err := form.TargetURL(baseURL)
+		if err != nil {
+			logger.Debug("Erro ao resolver action do formulário: %v", err)
+			continue
+		}
+
+		for _, input := range form.Inputs {
+			if ctx.Err() != nil {
+				break
+			}
+			// Botões de envio não costumam ser persistidos
+			if input.Name == "" || input.Type == "submit" {
+				continue
+			}
+
+			token := newCanary()
+			payload := fmt.Sprintf(storedXSSTemplate, token)
+
+			resp, err := sendRequest(ctx, form, baseURL, buildTestData(form, input.Name, payload), client)
+			if err != nil {
+				logger.Debug("Erro ao enviar payload armazenado para '%s': %v", input.Name, err)
+				continue
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+
+			injections[token] = storedInjection{
+				form:    form,
+				field:   input.Name,
+				payload: payload,
+				submit:  formPage(form, submitURL),
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	if len(injections) == 0 {
+		return nil
+	}
+
+	var results []StoredXSSResult
+	seen := make(map[string]bool)
+
+	for _, displayURL := range displayURLs {
+		if ctx.Err() != nil {
+			break
+		}
+		body, err := fetchPage(ctx, displayURL, client)
+		if err != nil {
+			logger.Debug("Erro ao revisitar %s: %v", displayURL, err)
+			continue
+		}
+
+		for _, token := range tokens {
+			inj := injections[token]
+			if !strings.Contains(body, token) {
+				continue
+			}
+			if !confirmBreakout(body, inj.payload, token, checkInjectedElement, Reflection{}) {
+				logger.Debug("Payload de '%s' armazenado com encoding em %s", inj.field, displayURL)
+				continue
+			}
+
+			key := token + "|" + displayURL
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			results = append(results, StoredXSSResult{
+				Field:      inj.field,
+				Payload:    inj.payload,
+				FormAction: inj.form.Action,
+				FormMethod: inj.form.Method,
+				SubmitURL:  inj.submit,
+				DisplayURL: displayURL,
+			})
+		}
+	}
+
+	return results
+}
+
+// formPage retorna a página onde o formulário foi encontrado, ou o destino do
+// envio quando ela não é conhecida
+func formPage(form Form, submitURL string) string {
+	if form.Page != "" {
+		return form.Page
+	}
+	return submitURL
+}
+
+// fetchPage busca o corpo de uma página de exibição
+func fetchPage(ctx context.Context, pageURL string, client *http.Client) (string, error) {
+	resp, err := httpGet(ctx, client, pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
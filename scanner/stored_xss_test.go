@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// guestbook armazena o nome com encoding e a mensagem sem encoding, e exibe
+// ambos em uma página diferente da que recebe o envio
+func guestbook() *httptest.Server {
+	var mu sync.Mutex
+	var entries []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/assinar", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		entries = append(entries, fmt.Sprintf("<li><b>%s</b>: %s</li>",
+			html.EscapeString(r.PostForm.Get("nome")), r.PostForm.Get("mensagem")))
+		mu.Unlock()
+		fmt.Fprint(w, "<p>Obrigado!</p>")
+	})
+	mux.HandleFunc("/livro", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body><ul>"+strings.Join(entries, "")+"</ul></body></html>")
+	})
+	mux.HandleFunc("/sobre", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><p>Sobre</p></body></html>")
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestStoredXSSGuestbook(t *testing.T) {
+	// O teste grava dados no alvo e exige o nível intrusive
+	defer SetSafetyLevel(CurrentSafetyLevel())
+	SetSafetyLevel(SafetyIntrusive)
+
+	server := guestbook()
+	defer server.Close()
+
+	form := Form{
+		Action: "/assinar",
+		Method: "POST",
+		Page:   server.URL + "/contato",
+		Inputs: []Input{
+			{Name: "nome", Type: "text"},
+			{Name: "mensagem", Tag: "textarea", Type: "textarea"},
+			{Name: "enviar", Tag: "button", Type: "submit"},
+		},
+	}
+
+	results := TestStoredXSS(context.Background(), []Form{form}, server.URL,
+		[]string{server.URL + "/sobre", server.URL + "/livro"}, server.Client())
+
+	if len(results) != 1 {
+		t.Fatalf("esperado 1 XSS armazenado, obtido %d: %+v", len(results), results)
+	}
+
+	r := results[0]
+	if r.Field != "mensagem" {
+		t.Errorf("Field = %q, want %q", r.Field, "mensagem")
+	}
+	if r.SubmitURL != server.URL+"/contato" {
+		t.Errorf("SubmitURL = %q, want página do formulário", r.SubmitURL)
+	}
+	if r.DisplayURL != server.URL+"/livro" {
+		t.Errorf("DisplayURL = %q, want %q", r.DisplayURL, server.URL+"/livro")
+	}
+}
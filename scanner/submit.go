@@ -0,0 +1,252 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Encodings suportados no envio de formulários
+const (
+	EncodingURLEncoded = "application/x-www-form-urlencoded"
+	EncodingMultipart  = "multipart/form-data"
+	EncodingJSON       = "application/json"
+	EncodingTextPlain  = "text/plain"
+)
+
+// sendRequest envia requisição HTTP para o formulário, codificando os dados
+// de acordo com o método e o enctype do formulário. Falhas são registradas
+// no RequestFailures do contexto.
+func sendRequest(ctx context.Context, form Form, baseURL string, data url.Values, client *http.Client) (*http.Response, error) {
+	target, err := form.TargetURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := buildRequest(form, target, data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	recordFailure(ctx, err)
+	return resp, err
+}
+
+// httpGet faz um GET que é interrompido com o cancelamento do contexto.
+// Falhas são registradas no RequestFailures do contexto.
+func httpGet(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	recordFailure(ctx, err)
+	return resp, err
+}
+
+// startTimer marca no contexto o momento em que a requisição é escrita na
+// conexão. O tempo retornado por elapsed começa nesse momento, sem contar a
+// espera no rate limiter e pela vaga no host, que acontecem antes no
+// transport. Quando o transport repete a requisição (429, 503, conexão
+// derrubada) vale a última tentativa, para o backoff não entrar na medição.
+// Sem requisição escrita (erro de conexão) conta desde a chamada.
+func startTimer(ctx context.Context) (traced context.Context, elapsed func() time.Duration) {
+	called := time.Now()
+	var wrote atomic.Pointer[time.Time]
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			now := time.Now()
+			wrote.Store(&now)
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), func() time.Duration {
+		if start := wrote.Load(); start != nil {
+			return time.Since(*start)
+		}
+		return time.Since(called)
+	}
+}
+
+// buildRequest monta a requisição de envio do formulário
+func buildRequest(form Form, target string, data url.Values) (*http.Request, error) {
+	method := strings.ToUpper(form.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var req *http.Request
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		// Em envios sem corpo a query da action é substituída pelos dados
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		u.RawQuery = data.Encode()
+		req, err = http.NewRequest(method, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		body, contentType, err := encodeBody(form, data)
+		if err != nil {
+			return nil, err
+		}
+		req, err = http.NewRequest(method, target, body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	for key, value := range form.Headers {
+		req.Header.Set(key, value)
+	}
+
+	return req, nil
+}
+
+// encodeBody codifica os dados segundo o enctype do formulário
+func encodeBody(form Form, data url.Values) (io.Reader, string, error) {
+	enctype := strings.ToLower(strings.TrimSpace(form.Enctype))
+	if i := strings.Index(enctype, ";"); i >= 0 {
+		enctype = strings.TrimSpace(enctype[:i])
+	}
+
+	switch enctype {
+	case EncodingMultipart:
+		return encodeMultipart(form, data)
+	case EncodingJSON:
+		body, err := encodeJSON(form, data)
+		return body, EncodingJSON, err
+	case EncodingTextPlain:
+		var buf bytes.Buffer
+		for _, key := range orderedKeys(form, data) {
+			for _, value := range data[key] {
+				fmt.Fprintf(&buf, "%s=%s\r\n", key, value)
+			}
+		}
+		return &buf, EncodingTextPlain, nil
+	default:
+		return strings.NewReader(data.Encode()), EncodingURLEncoded, nil
+	}
+}
+
+// encodeMultipart gera um corpo multipart/form-data. Campos do tipo file são
+// enviados como arquivos de texto com o valor do campo como conteúdo.
+func encodeMultipart(form Form, data url.Values) (io.Reader, string, error) {
+	fileFields := make(map[string]bool)
+	for _, input := range form.Inputs {
+		if input.Type == "file" {
+			fileFields[input.Name] = true
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, key := range orderedKeys(form, data) {
+		for _, value := range data[key] {
+			if !fileFields[key] {
+				if err := writer.WriteField(key, value); err != nil {
+					return nil, "", err
+				}
+				continue
+			}
+
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition",
+				fmt.Sprintf(`form-data; name="%s"; filename="test.txt"`, escapeQuotes(key)))
+			header.Set("Content-Type", "text/plain")
+			part, err := writer.CreatePart(header)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := io.WriteString(part, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// encodeJSON gera um objeto JSON com os dados. Campos numéricos e booleanos
+// mantêm o tipo quando o valor é compatível, para não quebrar a validação
+// do endpoint nos campos que não estão sendo atacados.
+func encodeJSON(form Form, data url.Values) (io.Reader, error) {
+	types := make(map[string]string)
+	for _, input := range form.Inputs {
+		types[input.Name] = input.Type
+	}
+
+	obj := make(map[string]interface{})
+	for _, key := range orderedKeys(form, data) {
+		value := data.Get(key)
+		switch types[key] {
+		case "number":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				obj[key] = n
+				continue
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(value); err == nil {
+				obj[key] = b
+				continue
+			}
+		}
+		obj[key] = value
+	}
+
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(body), nil
+}
+
+// orderedKeys retorna as chaves dos dados na ordem dos campos do formulário,
+// seguidas das chaves extras em ordem alfabética
+func orderedKeys(form Form, data url.Values) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, input := range form.Inputs {
+		if _, ok := data[input.Name]; ok && !seen[input.Name] {
+			seen[input.Name] = true
+			keys = append(keys, input.Name)
+		}
+	}
+
+	var extra []string
+	for key := range data {
+		if !seen[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(keys, extra...)
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,206 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"furador-de-coco/utils"
+)
+
+// echoHandler devolve o método, o content-type e os campos recebidos
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	fields := make(map[string]string)
+
+	switch {
+	case strings.HasPrefix(contentType, EncodingMultipart):
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for key, values := range r.MultipartForm.Value {
+			fields[key] = values[0]
+		}
+		for key, files := range r.MultipartForm.File {
+			f, _ := files[0].Open()
+			content, _ := io.ReadAll(f)
+			f.Close()
+			fields[key] = "file:" + files[0].Filename + ":" + string(content)
+		}
+	case contentType == EncodingJSON:
+		var obj map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for key, value := range obj {
+			fields[key] = fmt.Sprintf("%T:%v", value, value)
+		}
+	default:
+		r.ParseForm()
+		for key, values := range r.Form {
+			fields[key] = values[0]
+		}
+	}
+
+	var pairs []string
+	for key, value := range fields {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	fmt.Fprintf(w, "%s %s %s", r.Method, strings.SplitN(contentType, ";", 2)[0], strings.Join(pairs, "&"))
+}
+
+func TestSendRequestEncodings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(echoHandler))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		form Form
+		data url.Values
+		want string
+	}{
+		{
+			name: "GET substitui a query da action",
+			form: Form{Method: "GET", Action: "/search?old=1"},
+			data: url.Values{"q": {"x"}},
+			want: "GET  q=x",
+		},
+		{
+			name: "POST urlencoded",
+			form: Form{Method: "POST", Enctype: EncodingURLEncoded},
+			data: url.Values{"user": {"a"}},
+			want: "POST application/x-www-form-urlencoded user=a",
+		},
+		{
+			name: "Multipart com arquivo",
+			form: Form{
+				Method:  "POST",
+				Enctype: EncodingMultipart,
+				Inputs: []Input{
+					{Name: "nome", Type: "text"},
+					{Name: "foto", Type: "file"},
+				},
+			},
+			data: url.Values{"nome": {"<x>"}, "foto": {"payload"}},
+			want: "POST multipart/form-data foto=file:test.txt:payload&nome=<x>",
+		},
+		{
+			name: "JSON mantém tipos",
+			form: Form{
+				Method:  "POST",
+				Enctype: EncodingJSON,
+				Inputs: []Input{
+					{Name: "id", Type: "number"},
+					{Name: "ativo", Type: "boolean"},
+					{Name: "nome", Type: "text"},
+				},
+			},
+			data: url.Values{"id": {"7"}, "ativo": {"true"}, "nome": {"' OR 1=1--"}},
+			want: "POST application/json ativo=bool:true&id=float64:7&nome=string:' OR 1=1--",
+		},
+		{
+			name: "JSON com payload em campo numérico vira string",
+			form: Form{
+				Method:  "PATCH",
+				Enctype: EncodingJSON,
+				Inputs:  []Input{{Name: "id", Type: "number"}},
+			},
+			data: url.Values{"id": {"1 AND 1=1"}},
+			want: "PATCH application/json id=string:1 AND 1=1",
+		},
+		{
+			name: "PUT urlencoded",
+			form: Form{Method: "PUT"},
+			data: url.Values{"a": {"b"}},
+			want: "PUT application/x-www-form-urlencoded a=b",
+		},
+		{
+			name: "DELETE envia corpo",
+			form: Form{Method: "DELETE", Enctype: EncodingJSON},
+			data: url.Values{"id": {"3"}},
+			want: "DELETE application/json id=string:3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := sendRequest(context.Background(), tt.form, server.URL+"/page", tt.data, server.Client())
+			if err != nil {
+				t.Fatalf("sendRequest(context.Background(), ) error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.want {
+				t.Errorf("resposta = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIEndpointForm(t *testing.T) {
+	ep := APIEndpoint{
+		URL:     "https://api.example.com/users",
+		Headers: map[string]string{"Authorization": "Bearer x"},
+		Params:  map[string]interface{}{"name": "ana", "age": 30.0, "admin": false},
+	}
+
+	form, err := ep.Form()
+	if err != nil {
+		t.Fatalf("Form() error = %v", err)
+	}
+
+	if form.Method != "POST" || form.Enctype != EncodingJSON {
+		t.Errorf("Method/Enctype = %s/%s, want POST/%s", form.Method, form.Enctype, EncodingJSON)
+	}
+	if got := summarizeForm(form); got != "POST https://api.example.com/users [] admin,age,name" {
+		t.Errorf("form = %q", got)
+	}
+	if form.Inputs[1].Type != "number" || form.Inputs[0].Type != "boolean" {
+		t.Errorf("tipos dos campos não preservados: %+v", form.Inputs)
+	}
+}
+
+// A pausa do Retry-After entre as tentativas não entra no tempo medido
+func TestStartTimerRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	client := utils.LimitClient(server.Client(), utils.Limits{MaxRetries: 1})
+	traced, elapsed := startTimer(context.Background())
+	start := time.Now()
+	resp, err := httpGet(traced, client, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	measured, total := elapsed(), time.Since(start)
+
+	if calls.Load() != 2 || total < time.Second {
+		t.Fatalf("%d requisição(ões) em %v, esperado um 503 seguido de 200 após o Retry-After", calls.Load(), total)
+	}
+	if measured > 500*time.Millisecond {
+		t.Errorf("tempo medido = %v, inclui a pausa do Retry-After", measured)
+	}
+}
@@ -0,0 +1,600 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"furador-de-coco/finding"
+)
+
+// Tipos de matcher dos templates
+const (
+	MatcherStatus = "status"
+	MatcherWord   = "word"
+	MatcherRegex  = "regex"
+	MatcherHeader = "header"
+	MatcherTime   = "time"
+)
+
+// Template descreve uma verificação declarativa: a requisição enviada, os
+// conjuntos de payloads que preenchem os placeholders {{nome}} e os matchers
+// aplicados à resposta
+type Template struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Severity    string              `json:"severity"`
+	Safety      string              `json:"safety"`
+	Request     TemplateRequest     `json:"request"`
+	Payloads    map[string][]string `json:"payloads,omitempty"`
+	// Library usa os payloads da categoria da biblioteca como {{payload}}.
+	// Os modelos (breakouts xss e técnicas sqli) não entram.
+	Library string `json:"library,omitempty"`
+	// Metadados copiados para os findings
+	CWE         string `json:"cwe,omitempty"`
+	OWASP       string `json:"owasp,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+	// Confidence é confirmed, firm (padrão) ou tentative
+	Confidence string `json:"confidence,omitempty"`
+	// MatchersCondition é "or" (padrão, basta um matcher) ou "and" (todos)
+	MatchersCondition string    `json:"matchers-condition,omitempty"`
+	Matchers          []Matcher `json:"matchers"`
+
+	level SafetyLevel
+}
+
+// TemplateRequest é a requisição de um template. Path é relativo à origem
+// do alvo quando começa com "/" e à URL alvo nos demais casos; vazio usa a
+// própria URL alvo.
+type TemplateRequest struct {
+	Method  string            `json:"method,omitempty"`
+	Path    string            `json:"path"`
+	Params  map[string]string `json:"params,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Matcher verifica uma condição na resposta
+type Matcher struct {
+	Type string `json:"type"`
+	// Part é body (padrão), header ou all para word e regex
+	Part   string   `json:"part,omitempty"`
+	Words  []string `json:"words,omitempty"`
+	Regex  []string `json:"regex,omitempty"`
+	Status []int    `json:"status,omitempty"`
+	// Name é o header verificado pelo matcher header
+	Name string `json:"name,omitempty"`
+	// Duration é o tempo mínimo de resposta em segundos do matcher time
+	Duration float64 `json:"duration,omitempty"`
+	// Condition é "or" (padrão) ou "and" entre as palavras ou regex
+	Condition string `json:"condition,omitempty"`
+	// Negative inverte o resultado do matcher
+	Negative bool `json:"negative,omitempty"`
+
+	regexes []*regexp.Regexp
+}
+
+// templateResponse é o que os matchers enxergam de uma resposta
+type templateResponse struct {
+	status  int
+	header  http.Header
+	body    string
+	elapsed time.Duration
+}
+
+//go:embed templates/*.json
+var defaultTemplateFS embed.FS
+
+// DefaultTemplates carrega os templates embutidos no binário
+func DefaultTemplates() ([]*Template, error) {
+	var templates []*Template
+	err := fs.WalkDir(defaultTemplateFS, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := defaultTemplateFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t, err := ParseTemplate(path, data)
+		if err != nil {
+			return err
+		}
+		templates = append(templates, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// LoadTemplates carrega os templates embutidos e os arquivos .json de dir.
+// Templates com o mesmo id de um embutido o substituem.
+func LoadTemplates(dir string) ([]*Template, error) {
+	templates, err := DefaultTemplates()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return templates, nil
+	}
+
+	files, err := libraryFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		t, err := ParseTemplate(path, data)
+		if err != nil {
+			return nil, err
+		}
+
+		replaced := false
+		for i := range templates {
+			if templates[i].ID == t.ID {
+				templates[i] = t
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+// ParseTemplate decodifica e valida um template. Campos desconhecidos são
+// rejeitados para pegar erros de digitação.
+func ParseTemplate(path string, data []byte) (*Template, error) {
+	var t Template
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("%s: JSON inválido: %w", path, err)
+	}
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", path, t.ID, err)
+	}
+	return &t, nil
+}
+
+func (t *Template) validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("id é obrigatório")
+	}
+	if t.Name == "" {
+		return fmt.Errorf("name é obrigatório")
+	}
+	switch t.Severity {
+	case "CRITICAL", "HIGH", "MEDIUM", "LOW", "INFO":
+	default:
+		return fmt.Errorf("severidade inválida %q (use CRITICAL, HIGH, MEDIUM, LOW ou INFO)", t.Severity)
+	}
+	level, err := ParseSafetyLevel(t.Safety)
+	if err != nil {
+		return err
+	}
+	t.level = level
+
+	switch finding.Confidence(t.Confidence) {
+	case "", finding.ConfidenceConfirmed, finding.ConfidenceFirm, finding.ConfidenceTentative:
+	default:
+		return fmt.Errorf("confidence inválida %q (use confirmed, firm ou tentative)", t.Confidence)
+	}
+	if t.Library != "" && !payloadCategories[t.Library] {
+		return fmt.Errorf("categoria da biblioteca desconhecida %q", t.Library)
+	}
+	if t.Library != "" && t.Payloads["payload"] != nil {
+		return fmt.Errorf("library e payloads.payload são exclusivos")
+	}
+	if !validCondition(t.MatchersCondition) {
+		return fmt.Errorf("matchers-condition inválida %q (use and ou or)", t.MatchersCondition)
+	}
+	if len(t.Matchers) == 0 {
+		return fmt.Errorf("ao menos um matcher é obrigatório")
+	}
+
+	for i := range t.Matchers {
+		if err := t.Matchers[i].validate(); err != nil {
+			return fmt.Errorf("matcher %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (m *Matcher) validate() error {
+	if !validCondition(m.Condition) {
+		return fmt.Errorf("condition inválida %q (use and ou or)", m.Condition)
+	}
+	switch m.Part {
+	case "", "body", "header", "all":
+	default:
+		return fmt.Errorf("part inválida %q (use body, header ou all)", m.Part)
+	}
+
+	switch m.Type {
+	case MatcherStatus:
+		if len(m.Status) == 0 {
+			return fmt.Errorf("status é obrigatório")
+		}
+	case MatcherWord:
+		if len(m.Words) == 0 {
+			return fmt.Errorf("words é obrigatório")
+		}
+	case MatcherRegex:
+		if len(m.Regex) == 0 {
+			return fmt.Errorf("regex é obrigatório")
+		}
+	case MatcherHeader:
+		if m.Name == "" {
+			return fmt.Errorf("name é obrigatório")
+		}
+	case MatcherTime:
+		if m.Duration <= 0 {
+			return fmt.Errorf("duration deve ser > 0")
+		}
+	default:
+		return fmt.Errorf("tipo desconhecido %q", m.Type)
+	}
+
+	regexes, err := compileRegexes(m.Regex)
+	if err != nil {
+		return err
+	}
+	m.regexes = regexes
+	return nil
+}
+
+func validCondition(condition string) bool {
+	return condition == "" || condition == "and" || condition == "or"
+}
+
+// Level retorna o nível de segurança do template
+func (t *Template) Level() SafetyLevel {
+	return t.level
+}
+
+// combinations retorna todas as combinações dos conjuntos de payloads (um
+// valor de cada conjunto). Sem payloads há uma única combinação vazia.
+func (t *Template) combinations() []map[string]string {
+	sets := make(map[string][]string, len(t.Payloads)+1)
+	for name, values := range t.Payloads {
+		sets[name] = values
+	}
+	if t.Library != "" {
+		var values []string
+		for _, p := range library.ByCategory(t.Library) {
+			if !p.IsTemplate() {
+				values = append(values, p.Payload)
+			}
+		}
+		sets["payload"] = values
+	}
+
+	combos := []map[string]string{{}}
+	for _, name := range sortedKeys(sets) {
+		var next []map[string]string
+		for _, combo := range combos {
+			for _, value := range sets[name] {
+				c := make(map[string]string, len(combo)+1)
+				for k, v := range combo {
+					c[k] = v
+				}
+				c[name] = value
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// RunTemplates executa os templates permitidos no nível de segurança atual
+// contra o alvo. Cada combinação de payloads vira um job do worker pool.
+// Templates com requisições que falharam são retornados como inconclusivos,
+// um registro por template.
+func RunTemplates(ctx context.Context, templates []*Template, baseURL string, client *http.Client, workers int) CheckResults {
+	var jobs []ScanJob
+	var saved []ScanJobResult
+	for _, t := range templates {
+		if !Allowed(t.level) {
+			continue
+		}
+		for i, values := range t.combinations() {
+			job := ScanJob{BaseURL: baseURL, Template: t, Values: values, key: templateKey(t, i)}
+			if result, ok := LookupJob(ctx, job.key); ok {
+				saved = append(saved, result)
+				continue
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 && len(saved) == 0 {
+		return CheckResults{}
+	}
+
+	pool := NewWorkerPool(workers)
+	pool.Start(ctx, client)
+	go func() {
+		for _, job := range jobs {
+			if ctx.Err() != nil {
+				break
+			}
+			pool.Submit(job)
+		}
+		pool.Close()
+	}()
+
+	// Jobs concluídos em um scan anterior entram com os novos
+	jobResults := saved
+	for jobResult := range pool.Results() {
+		jobResults = append(jobResults, jobResult)
+	}
+
+	var findings []finding.Finding
+	var inconclusive []finding.Inconclusive
+	var timings []CheckTiming
+	byTemplate := map[string]int{}
+	for _, jobResult := range jobResults {
+		findings = append(findings, jobResult.Findings...)
+		timings = append(timings, jobResult.Timings...)
+		for _, inc := range jobResult.Inconclusive {
+			if i, ok := byTemplate[inc.CheckID]; ok {
+				inconclusive[i].Failures += inc.Failures
+				inconclusive[i].Reason = inc.Reason
+				continue
+			}
+			byTemplate[inc.CheckID] = len(inconclusive)
+			inconclusive = append(inconclusive, inc)
+		}
+	}
+	sort.SliceStable(inconclusive, func(i, j int) bool {
+		return inconclusive[i].CheckID < inconclusive[j].CheckID
+	})
+
+	// A ordem de conclusão dos workers varia; ordena para relatórios estáveis
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].CheckID != findings[j].CheckID {
+			return findings[i].CheckID < findings[j].CheckID
+		}
+		return findings[i].Payload < findings[j].Payload
+	})
+	return CheckResults{Findings: findings, Inconclusive: inconclusive, Timings: SummarizeTimings(timings)}
+}
+
+// checkID identifica os findings do template nos relatórios
+func (t *Template) checkID() string {
+	return "template:" + t.ID
+}
+
+// Execute envia a requisição do template com os valores dos placeholders e
+// retorna um finding se os matchers forem satisfeitos
+func (t *Template) Execute(ctx context.Context, baseURL string, values map[string]string, client *http.Client) ([]finding.Finding, error) {
+	req, err := t.buildRequest(baseURL, values)
+	if err != nil {
+		return nil, err
+	}
+
+	traced, elapsed := startTimer(ctx)
+	resp, err := client.Do(req.WithContext(traced))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, ok := t.match(templateResponse{
+		status:  resp.StatusCode,
+		header:  resp.Header,
+		body:    string(body),
+		elapsed: elapsed(),
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	description := t.Description
+	if description == "" {
+		description = t.Name
+	}
+	confidence := finding.Confidence(t.Confidence)
+	if confidence == "" {
+		confidence = finding.ConfidenceFirm
+	}
+	return []finding.Finding{{
+		CheckID:     t.checkID(),
+		Title:       t.Name,
+		Severity:    finding.Severity(t.Severity),
+		Confidence:  confidence,
+		CWE:         t.CWE,
+		OWASP:       t.OWASP,
+		URL:         req.URL.String(),
+		Method:      req.Method,
+		Payload:     values["payload"],
+		Evidence:    evidence,
+		Description: description,
+		Response:    truncateString(string(body), 500),
+		Remediation: t.Remediation,
+	}}, nil
+}
+
+// buildRequest monta a requisição substituindo os placeholders. Em path e
+// body o valor entra como está; em params ele é codificado na query.
+func (t *Template) buildRequest(baseURL string, values map[string]string) (*http.Request, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fillPlaceholders(t.Request.Path, values)
+	target := baseURL
+	switch {
+	case strings.HasPrefix(path, "/"):
+		target = base.Scheme + "://" + base.Host + path
+	case path != "":
+		target = strings.TrimRight(baseURL, "/") + "/" + path
+	}
+
+	if len(t.Request.Params) > 0 {
+		params := url.Values{}
+		for name, value := range t.Request.Params {
+			params.Set(name, fillPlaceholders(value, values))
+		}
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + params.Encode()
+	}
+
+	method := strings.ToUpper(t.Request.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if t.Request.Body != "" {
+		body = strings.NewReader(fillPlaceholders(t.Request.Body, values))
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range t.Request.Headers {
+		req.Header.Set(name, fillPlaceholders(value, values))
+	}
+	return req, nil
+}
+
+func fillPlaceholders(s string, values map[string]string) string {
+	for name, value := range values {
+		s = strings.ReplaceAll(s, "{{"+name+"}}", value)
+	}
+	return s
+}
+
+// match aplica os matchers e retorna a evidência do primeiro satisfeito
+func (t *Template) match(resp templateResponse) (string, bool) {
+	var evidence []string
+	for _, m := range t.Matchers {
+		found, ev := m.match(resp)
+		if t.MatchersCondition == "and" && !found {
+			return "", false
+		}
+		if found && ev != "" {
+			evidence = append(evidence, ev)
+		}
+		if found && t.MatchersCondition != "and" {
+			return strings.Join(evidence, "; "), true
+		}
+	}
+	if t.MatchersCondition == "and" {
+		return strings.Join(evidence, "; "), true
+	}
+	return "", false
+}
+
+func (m Matcher) match(resp templateResponse) (bool, string) {
+	found, evidence := m.matchPositive(resp)
+	if m.Negative {
+		return !found, ""
+	}
+	return found, evidence
+}
+
+func (m Matcher) matchPositive(resp templateResponse) (bool, string) {
+	switch m.Type {
+	case MatcherStatus:
+		for _, status := range m.Status {
+			if resp.status == status {
+				return true, fmt.Sprintf("status %d", status)
+			}
+		}
+		return false, ""
+
+	case MatcherTime:
+		if resp.elapsed.Seconds() >= m.Duration {
+			return true, fmt.Sprintf("resposta em %v", resp.elapsed.Round(time.Millisecond))
+		}
+		return false, ""
+
+	case MatcherHeader:
+		value := resp.header.Get(m.Name)
+		if value == "" {
+			return false, ""
+		}
+		if len(m.Words) == 0 && len(m.regexes) == 0 {
+			return true, m.Name + ": " + value
+		}
+		if found, _ := m.matchText(value); found {
+			return true, m.Name + ": " + value
+		}
+		return false, ""
+	}
+
+	return m.matchText(m.partText(resp))
+}
+
+// matchText aplica as palavras e regex ao texto respeitando a condição
+func (m Matcher) matchText(text string) (bool, string) {
+	var matched []string
+	for _, word := range m.Words {
+		if strings.Contains(text, word) {
+			matched = append(matched, word)
+		} else if m.Condition == "and" {
+			return false, ""
+		}
+	}
+	for _, re := range m.regexes {
+		if s := re.FindString(text); s != "" {
+			matched = append(matched, s)
+		} else if m.Condition == "and" {
+			return false, ""
+		}
+	}
+	if len(matched) == 0 {
+		return false, ""
+	}
+	return true, strings.Join(matched, ", ")
+}
+
+func (m Matcher) partText(resp templateResponse) string {
+	var headers strings.Builder
+	if m.Part == "header" || m.Part == "all" {
+		for _, name := range sortedKeys(url.Values(resp.header)) {
+			for _, value := range resp.header[name] {
+				fmt.Fprintf(&headers, "%s: %s\n", name, value)
+			}
+		}
+	}
+
+	switch m.Part {
+	case "header":
+		return headers.String()
+	case "all":
+		return headers.String() + "\n" + resp.body
+	default:
+		return resp.body
+	}
+}
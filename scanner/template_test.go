@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultTemplatesValid(t *testing.T) {
+	if _, err := DefaultTemplates(); err != nil {
+		t.Fatalf("templates embutidos inválidos: %v", err)
+	}
+}
+
+func TestRunTemplates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.git/config":
+			fmt.Fprint(w, "[core]\n\trepositoryformatversion = 0\n")
+		case "/.env":
+			// Página de erro amigável com status 200 não conta
+			fmt.Fprint(w, "<!DOCTYPE html><html>API_KEY=abc</html>")
+		case "/app/view":
+			if strings.Contains(r.URL.Query().Get("file"), "passwd") {
+				fmt.Fprint(w, "root:x:0:0:root:/root:/bin/bash")
+				return
+			}
+			fmt.Fprint(w, "ok")
+		case "/app/slow":
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprint(w, "ok")
+		default:
+			if origin := r.Header.Get("Origin"); origin != "" {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			fmt.Fprint(w, "ok")
+		}
+	}))
+	defer server.Close()
+
+	custom := []string{
+		`{"id": "view-file", "name": "Leitura de arquivo", "severity": "CRITICAL", "safety": "safe",
+		  "request": {"path": "view", "params": {"file": "{{payload}}"}},
+		  "payloads": {"payload": ["index.html", "../../etc/passwd"]},
+		  "matchers": [{"type": "regex", "regex": ["root:[^:]*:0:0:"]}]}`,
+		`{"id": "slow", "name": "Resposta lenta", "severity": "LOW", "safety": "safe",
+		  "request": {"path": "slow"},
+		  "matchers": [{"type": "time", "duration": 0.15}]}`,
+		`{"id": "intrusive", "name": "Intrusivo", "severity": "LOW", "safety": "intrusive",
+		  "request": {"path": "slow"},
+		  "matchers": [{"type": "status", "status": [200]}]}`,
+	}
+	templates, err := DefaultTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, data := range custom {
+		tmpl, err := ParseTemplate(fmt.Sprintf("custom%d.json", i), []byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		templates = append(templates, tmpl)
+	}
+
+	results := RunTemplates(context.Background(), templates, server.URL+"/app/", server.Client(), 3)
+
+	if len(results.Inconclusive) > 0 {
+		t.Errorf("inconclusivos inesperados: %v", results.Inconclusive)
+	}
+
+	got := map[string]string{}
+	for _, f := range results.Findings {
+		got[f.Title] = f.Evidence
+	}
+	want := map[string]bool{
+		"Repositório Git exposto": true,
+		"Leitura de arquivo":      true,
+		"Resposta lenta":          true,
+	}
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			t.Errorf("template %q não detectado (resultados: %v)", name, got)
+		}
+	}
+	for name := range got {
+		if !want[name] {
+			t.Errorf("falso positivo: %q", name)
+		}
+	}
+	if ev := got["Leitura de arquivo"]; ev != "root:x:0:0:" {
+		t.Errorf("evidência = %q", ev)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"Campo desconhecido", `{"id": "a", "name": "A", "severity": "LOW", "safety": "safe", "matcher": []}`, "JSON inválido"},
+		{"Severidade", `{"id": "a", "name": "A", "severity": "grave", "safety": "safe", "matchers": [{"type": "status", "status": [200]}]}`, "severidade inválida"},
+		{"Sem matchers", `{"id": "a", "name": "A", "severity": "LOW", "safety": "safe"}`, "matcher é obrigatório"},
+		{"Tipo desconhecido", `{"id": "a", "name": "A", "severity": "LOW", "safety": "safe", "matchers": [{"type": "dsl"}]}`, "tipo desconhecido"},
+		{"Regex inválida", `{"id": "a", "name": "A", "severity": "LOW", "safety": "safe", "matchers": [{"type": "regex", "regex": ["("]}]}`, "regex inválida"},
+		{"Categoria da biblioteca", `{"id": "a", "name": "A", "severity": "LOW", "safety": "safe", "library": "rce", "matchers": [{"type": "status", "status": [200]}]}`, "categoria da biblioteca"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate("t.json", []byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("erro = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"sort"
+	"time"
+)
+
+// CheckTiming é o tempo gasto por uma verificação. Cada resultado do pool
+// traz o tempo das verificações que o job executou; SummarizeTimings soma
+// os jobs por verificação.
+type CheckTiming struct {
+	CheckID  string
+	Runs     int           // Alvos (ou formulários) em que a verificação rodou
+	Errors   int           // Execuções que terminaram com CheckError
+	Duration time.Duration // Soma do tempo dos workers, incluindo a espera do limite de taxa
+}
+
+// SummarizeTimings soma os tempos por verificação, da mais demorada para a
+// mais rápida
+func SummarizeTimings(timings []CheckTiming) []CheckTiming {
+	byCheck := map[string]int{}
+	var summary []CheckTiming
+	for _, t := range timings {
+		i, ok := byCheck[t.CheckID]
+		if !ok {
+			byCheck[t.CheckID] = len(summary)
+			summary = append(summary, t)
+			continue
+		}
+		summary[i].Runs += t.Runs
+		summary[i].Errors += t.Errors
+		summary[i].Duration += t.Duration
+	}
+
+	sort.SliceStable(summary, func(i, j int) bool {
+		if summary[i].Duration != summary[j].Duration {
+			return summary[i].Duration > summary[j].Duration
+		}
+		return summary[i].CheckID < summary[j].CheckID
+	})
+	return summary
+}
@@ -1,25 +1,42 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"sync"
 	"time"
+
+	"furador-de-coco/finding"
 )
 
 // WorkerPool gerencia workers para processar formulários em paralelo
 type WorkerPool struct {
-	workers   int
-	jobs      chan ScanJob
-	results   chan ScanJobResult
-	wg        sync.WaitGroup
-	rateLimit time.Duration
+	workers int
+	jobs    chan ScanJob
+	results chan ScanJobResult
+	wg      sync.WaitGroup
 }
 
-// ScanJob representa um trabalho de scan
+// ScanJob representa um trabalho de scan. Com Template preenchido o job é
+// uma requisição do template com os valores de Values; com Check, a
+// execução da verificação sobre Target. Form, BaseURL e FormIndex descrevem
+// os formulários passados a ScanForms, que os divide em unidades de trabalho
+// por campo e payload.
 type ScanJob struct {
 	Form      Form
 	BaseURL   string
 	FormIndex int
+
+	Template *Template
+	Values   map[string]string
+
+	Check  Check
+	Target Target
+
+	key  string // Chave do job no journal (vazia para as unidades de formulário)
+	pos  int    // Posição do job, para quem submete reordenar os resultados
+	unit *fieldUnit
 }
 
 // ScanJobResult representa o resultado de um scan
@@ -30,24 +47,63 @@ type ScanJobResult struct {
 	SQLiVuln    bool
 	XSSResults  []XSSResult
 	SQLiResults []SQLiResult
-	Error       error
+
+	// Falhas das verificações do job (*CheckError, uma por verificação,
+	// combinadas com errors.Join) e o erro do contexto se o scan foi
+	// interrompido. Não é salvo no estado do scan: jobs com erro não são
+	// registrados.
+	Error error `json:"-"`
+
+	// Findings dos jobs de template e de verificação
+	Findings []finding.Finding
+
+	// Verificações do job que não puderam ser concluídas por falha nas
+	// requisições
+	Inconclusive []finding.Inconclusive
+
+	// Tempo gasto por verificação do job
+	Timings []CheckTiming
+
+	// Saída de uma unidade de trabalho de ScanForms (contextos do canary XSS
+	// e banco confirmado pelas sondas), salva no journal para remontar o
+	// formulário num scan retomado
+	Reflections []Reflection `json:",omitempty"`
+	DBMS        DBMS         `json:",omitempty"`
+
+	pos  int
+	unit *fieldUnit
+}
+
+// finishCheck fecha a execução de uma verificação no job: registra o tempo
+// e, se alguma requisição falhou, o alvo como inconclusivo e a falha em
+// Error (como *CheckError)
+func (r *ScanJobResult) finishCheck(failures *RequestFailures, checkID, url, parameter string, start time.Time) {
+	timing := CheckTiming{CheckID: checkID, Runs: 1, Duration: time.Since(start)}
+	if err := failures.CheckError(checkID, url, parameter); err != nil {
+		timing.Errors = 1
+		r.Error = errors.Join(r.Error, err)
+		r.Inconclusive = append(r.Inconclusive, *failures.Inconclusive(checkID, url, parameter))
+	}
+	r.Timings = append(r.Timings, timing)
 }
 
-// NewWorkerPool cria um novo pool de workers
-func NewWorkerPool(workers int, rateLimit time.Duration) *WorkerPool {
+// NewWorkerPool cria um novo pool de workers. A taxa de requisições é
+// controlada pelo transport do client (utils.LimitClient), não pelo pool.
+func NewWorkerPool(workers int) *WorkerPool {
 	return &WorkerPool{
-		workers:   workers,
-		jobs:      make(chan ScanJob, workers*2),
-		results:   make(chan ScanJobResult, workers*2),
-		rateLimit: rateLimit,
+		workers: workers,
+		jobs:    make(chan ScanJob, workers*2),
+		results: make(chan ScanJobResult, workers*2),
 	}
 }
 
-// Start inicia os workers
-func (wp *WorkerPool) Start(client *http.Client) {
+// Start inicia os workers. Com o contexto cancelado os workers param de
+// executar jobs: os jobs restantes são devolvidos sem rodar, com Error
+// preenchido, para que quem coleta os resultados não fique esperando.
+func (wp *WorkerPool) Start(ctx context.Context, client *http.Client) {
 	for i := 0; i < wp.workers; i++ {
 		wp.wg.Add(1)
-		go wp.worker(client)
+		go wp.worker(ctx, client)
 	}
 }
 
@@ -69,39 +125,69 @@ func (wp *WorkerPool) Results() <-chan ScanJobResult {
 }
 
 // worker processa jobs do pool
-func (wp *WorkerPool) worker(client *http.Client) {
+func (wp *WorkerPool) worker(ctx context.Context, client *http.Client) {
 	defer wp.wg.Done()
 
 	for job := range wp.jobs {
 		result := ScanJobResult{
 			FormIndex: job.FormIndex,
 			Form:      job.Form,
+			pos:       job.pos,
+			unit:      job.unit,
 		}
 
-		// Testa XSS com resultados detalhados
-		result.XSSResults = TestXSSDetailed(job.Form, job.BaseURL, client)
-		for _, xssResult := range result.XSSResults {
-			if xssResult.Vulnerable {
-				result.XSSVuln = true
-				break
-			}
+		if err := ctx.Err(); err != nil {
+			result.Error = err
+			wp.results <- result
+			continue
 		}
 
-		// Rate limiting entre testes
-		time.Sleep(wp.rateLimit)
+		switch {
+		case job.Template != nil:
+			runTemplate(ctx, job, client, &result)
+		case job.Check != nil:
+			runCheck(ctx, job.Check, job.Target, client, &result)
+		case job.unit != nil:
+			job.unit.run(ctx, client)
+		}
+		if err := ctx.Err(); err != nil {
+			result.Error = errors.Join(result.Error, err)
+		}
+		if job.key != "" {
+			RecordJob(ctx, job.key, result)
+		}
+		wp.results <- result
+	}
+}
 
-		// Testa SQLi com resultados detalhados
-		result.SQLiResults = TestSQLiDetailed(job.Form, job.BaseURL, client)
-		for _, sqliResult := range result.SQLiResults {
-			if sqliResult.Vulnerable {
-				result.SQLiVuln = true
-				break
-			}
+// runTemplate executa a requisição do template do job. Erros ao montar ou
+// enviar a requisição tornam o template inconclusivo no alvo.
+func runTemplate(ctx context.Context, job ScanJob, client *http.Client, result *ScanJobResult) {
+	tracked, failures := TrackFailures(ctx)
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			failures.recordPanic(v)
 		}
+		result.finishCheck(failures, job.Template.checkID(), job.BaseURL, "", start)
+	}()
 
-		wp.results <- result
+	findings, err := job.Template.Execute(tracked, job.BaseURL, job.Values, client)
+	recordFailure(tracked, err)
+	result.Findings = findings
+}
 
-		// Rate limiting após conclusão do job
-		time.Sleep(wp.rateLimit)
-	}
+// runCheck executa a verificação sobre o alvo. Um panic na verificação é
+// tratado como falha do alvo, sem derrubar o worker.
+func runCheck(ctx context.Context, c Check, target Target, client *http.Client, result *ScanJobResult) {
+	tracked, failures := TrackFailures(ctx)
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			failures.recordPanic(v)
+		}
+		result.finishCheck(failures, c.ID(), target.URL, target.Parameter, start)
+	}()
+
+	result.Findings = c.Run(tracked, target, client)
 }
@@ -1,33 +1,12 @@
 package scanner
 
 import (
+	"context"
 	"io"
 	"net/http"
-	"net/url"
 	"strings"
-	"time"
 )
 
-// XSSPayload representa um payload de teste XSS
-type XSSPayload struct {
-	Payload     string
-	Description string
-}
-
-// Payloads avançados para detecção de XSS
-var xssPayloads = []XSSPayload{
-	{Payload: "<script>alert('XSS')</script>", Description: "Script básico"},
-	{Payload: "<img src=x onerror=alert('XSS')>", Description: "Event handler em IMG"},
-	{Payload: "<svg/onload=alert('XSS')>", Description: "SVG onload"},
-	{Payload: "javascript:alert('XSS')", Description: "JavaScript protocol"},
-	{Payload: "<iframe src=javascript:alert('XSS')>", Description: "Iframe javascript"},
-	{Payload: "<body onload=alert('XSS')>", Description: "Body onload"},
-	{Payload: "'\"><script>alert('XSS')</script>", Description: "Breaking attributes"},
-	{Payload: "<img src=\"x\" onerror=\"alert('XSS')\">", Description: "Quoted event handler"},
-	{Payload: "<ScRiPt>alert('XSS')</ScRiPt>", Description: "Case variation"},
-	{Payload: "<<SCRIPT>alert('XSS');//<</SCRIPT>", Description: "Nested tags"},
-}
-
 // XSSResult armazena o resultado de um teste XSS
 type XSSResult struct {
 	Vulnerable  bool
@@ -35,125 +14,112 @@ type XSSResult struct {
 	Description string
 	Response    string
 	Field       string
+	Context     string // Contexto da reflexão explorado (ex.: attr-quoted, script)
 }
 
-// TestXSS testa vulnerabilidades XSS em um formulário
-func TestXSS(form Form, baseURL string, client *http.Client) bool {
-	for _, xssPayload := range xssPayloads {
-		if testSingleXSS(form, baseURL, client, xssPayload.Payload) {
-			return true
+// TestXSSDetailed testa XSS e retorna resultados detalhados. Cada campo
+// recebe primeiro um canary único para descobrir onde o valor é refletido;
+// depois são enviados payloads de breakout adequados a cada contexto, e a
+// vulnerabilidade só é confirmada quando o DOM da resposta mostra o breakout.
+func TestXSSDetailed(ctx context.Context, form Form, baseURL string, client *http.Client) []XSSResult {
+	var results []XSSResult
+	for _, input := range form.Inputs {
+		if ctx.Err() != nil {
+			break
 		}
-		time.Sleep(50 * time.Millisecond) // Rate limiting
+		results = append(results, testXSSField(ctx, form, baseURL, input.Name, client)...)
 	}
-	return false
+	return results
 }
 
-// TestXSSDetailed testa XSS e retorna resultados detalhados
-func TestXSSDetailed(form Form, baseURL string, client *http.Client) []XSSResult {
-	var results []XSSResult
+// testXSSField testa XSS em um único campo do formulário
+func testXSSField(ctx context.Context, form Form, baseURL, field string, client *http.Client) []XSSResult {
+	reflections, results := probeXSSField(ctx, form, baseURL, field, client)
+	if len(reflections) == 0 {
+		return results
+	}
+	return testReflections(ctx, form, baseURL, field, reflections, client)
+}
 
-	for _, xssPayload := range xssPayloads {
-		for _, field := range form.Inputs {
-			data := url.Values{}
-			// Preenche outros campos com valores normais
-			for _, input := range form.Inputs {
-				if input == field {
-					data.Set(input, xssPayload.Payload)
-				} else {
-					data.Set(input, "test")
-				}
-			}
+// probeXSSField envia um canary no campo e retorna os contextos em que ele é
+// refletido. Sem reflexão, retorna o resultado "não refletido" do campo.
+func probeXSSField(ctx context.Context, form Form, baseURL, field string, client *http.Client) ([]Reflection, []XSSResult) {
+	canary := newCanary()
+	body, err := submitField(ctx, form, baseURL, field, canary, client)
+	if err != nil {
+		return nil, nil
+	}
 
-			target := baseURL + form.Action
-			var res *http.Response
-			var err error
+	reflections := findReflections(body, canary)
+	if len(reflections) == 0 {
+		return nil, []XSSResult{{
+			Payload:     canary,
+			Description: "Valor não refletido na resposta",
+			Response:    truncateString(body, 500),
+			Field:       field,
+		}}
+	}
+	return reflections, nil
+}
 
-			if form.Method == "POST" {
-				res, err = client.PostForm(target, data)
-			} else {
-				fullURL := target + "?" + data.Encode()
-				res, err = client.Get(fullURL)
-			}
+// testReflections envia os payloads de breakout de cada contexto em que o
+// campo é refletido, parando no primeiro breakout confirmado
+func testReflections(ctx context.Context, form Form, baseURL, field string, reflections []Reflection, client *http.Client) []XSSResult {
+	var results []XSSResult
 
-			if err != nil {
+	for _, reflection := range reflections {
+		for _, cp := range payloadsFor(reflection) {
+			if ctx.Err() != nil {
+				break
+			}
+			result, ok := testBreakout(ctx, form, baseURL, field, reflection, cp, client)
+			if !ok {
 				continue
 			}
+			results = append(results, result)
 
-			buf := new(strings.Builder)
-			io.Copy(buf, res.Body)
-			body := buf.String()
-			res.Body.Close()
-
-			vulnerable := detectXSS(body, xssPayload.Payload)
-
-			results = append(results, XSSResult{
-				Vulnerable:  vulnerable,
-				Payload:     xssPayload.Payload,
-				Description: xssPayload.Description,
-				Response:    truncateString(body, 500),
-				Field:       field,
-			})
-
-			if vulnerable {
-				break // Vulnerável encontrado, não precisa testar outros payloads neste campo
+			if result.Vulnerable {
+				return results // Breakout confirmado, não precisa testar outros payloads neste campo
 			}
-
-			time.Sleep(50 * time.Millisecond)
 		}
 	}
 
 	return results
 }
 
-func testSingleXSS(form Form, baseURL string, client *http.Client, payload string) bool {
-	data := url.Values{}
+// testBreakout envia um payload de breakout para o contexto da reflexão e
+// confirma o breakout no DOM da resposta. ok é false se a requisição falhou.
+func testBreakout(ctx context.Context, form Form, baseURL, field string, reflection Reflection, cp contextPayload, client *http.Client) (result XSSResult, ok bool) {
+	token := newCanary()
+	payload, description := cp.render(reflection, token)
 
-	for _, input := range form.Inputs {
-		data.Set(input, payload)
+	body, err := submitField(ctx, form, baseURL, field, payload, client)
+	if err != nil {
+		return XSSResult{}, false
 	}
 
-	target := baseURL + form.Action
-	var res *http.Response
-	var err error
+	return XSSResult{
+		Vulnerable:  confirmBreakout(body, payload, token, cp.Check, reflection),
+		Payload:     payload,
+		Description: description,
+		Response:    truncateString(body, 500),
+		Field:       field,
+		Context:     string(reflection.Context),
+	}, true
+}
 
-	if form.Method == "POST" {
-		res, err = client.PostForm(target, data)
-	} else {
-		fullURL := target + "?" + data.Encode()
-		res, err = client.Get(fullURL)
-	}
+// submitField envia o formulário com value no campo alvo e retorna o corpo
+// da resposta
+func submitField(ctx context.Context, form Form, baseURL, field, value string, client *http.Client) (string, error) {
+	data := buildTestData(form, field, value)
 
+	res, err := sendRequest(ctx, form, baseURL, data, client)
 	if err != nil {
-		return false
+		return "", err
 	}
 	defer res.Body.Close()
 
 	buf := new(strings.Builder)
 	io.Copy(buf, res.Body)
-	body := buf.String()
-
-	return detectXSS(body, payload)
-}
-
-// detectXSS verifica se o payload aparece na resposta de forma vulnerável
-func detectXSS(body, payload string) bool {
-	// Verifica se o payload aparece sem encoding
-	if strings.Contains(body, payload) {
-		return true
-	}
-
-	// Verifica variações comuns do payload
-	variations := []string{
-		strings.ToLower(payload),
-		strings.ToUpper(payload),
-		strings.ReplaceAll(payload, "'", "\""),
-	}
-
-	for _, variation := range variations {
-		if strings.Contains(strings.ToLower(body), strings.ToLower(variation)) {
-			return true
-		}
-	}
-
-	return false
+	return buf.String(), nil
 }
@@ -0,0 +1,356 @@
+// Package state salva o progresso de um scan em um arquivo JSON-lines para
+// que ele possa ser retomado depois de uma queda de VPN, do computador
+// dormir ou de um Ctrl-C. Cada linha é um registro acrescentado ao fim do
+// arquivo: o cabeçalho com o comando e o hash da configuração, os alvos
+// descobertos e cada job concluído.
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"furador-de-coco/report"
+	"furador-de-coco/scanner"
+)
+
+// Versão do formato do arquivo; estados de outra versão não são retomados
+const version = 1
+
+// syncInterval é o intervalo entre as gravações em disco dos jobs
+// concluídos. Uma queda do sistema perde no máximo esse intervalo de jobs,
+// que o scan retomado roda de novo.
+const syncInterval = time.Second
+
+// Tipos de registro
+const (
+	recordHeader  = "header"
+	recordTargets = "targets"
+	recordJob     = "job"
+)
+
+// record é uma linha do arquivo de estado
+type record struct {
+	Type string `json:"type"`
+
+	// Cabeçalho
+	Version int       `json:"version,omitempty"`
+	Command string    `json:"command,omitempty"`
+	Config  string    `json:"config,omitempty"`
+	Target  string    `json:"target,omitempty"`
+	Started time.Time `json:"started,omitzero"`
+
+	Targets *Targets `json:"targets,omitempty"`
+
+	Key    string                 `json:"key,omitempty"`
+	Result *scanner.ScanJobResult `json:"result,omitempty"`
+}
+
+// Targets é o que foi descoberto antes do scan. Um scan retomado usa os
+// alvos salvos em vez de percorrer o site de novo, o que mantém estáveis
+// as chaves dos jobs.
+type Targets struct {
+	Forms     []scanner.Form
+	Pages     []string
+	Endpoints []report.Endpoint
+}
+
+// ErrIncompatible indica um estado salvo por outro comando, outra versão
+// ou outra configuração
+var ErrIncompatible = errors.New("estado incompatível")
+
+// File é o arquivo de estado de um scan. Implementa scanner.Journal.
+//
+// Os registros de jobs vão para um buffer, gravado e sincronizado com o
+// disco a cada syncInterval; o cabeçalho e os alvos são sincronizados na
+// hora e Close grava o que falta.
+type File struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	buf     *bufio.Writer
+	started time.Time
+	targets *Targets
+	jobs    map[string]scanner.ScanJobResult
+	err     error // Primeira falha de escrita
+	closed  bool
+
+	stop chan struct{} // Encerra a sincronização periódica
+	done chan struct{} // Fechado quando a sincronização periódica termina
+}
+
+// Create cria um arquivo de estado novo em dir para o comando, com o hash da
+// configuração (config.Config.Hash) e a URL alvo
+func Create(dir, command, configHash, target string) (*File, error) {
+	started := time.Now()
+	path := filepath.Join(dir, "estado-"+command+"-"+started.Format("20060102-150405")+".jsonl")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar estado: %w", err)
+	}
+
+	f := &File{
+		path:    path,
+		file:    file,
+		buf:     bufio.NewWriter(file),
+		started: started,
+		jobs:    map[string]scanner.ScanJobResult{},
+	}
+	f.append(record{
+		Type:    recordHeader,
+		Version: version,
+		Command: command,
+		Config:  configHash,
+		Target:  target,
+		Started: started,
+	})
+	f.sync()
+	if f.err != nil {
+		file.Close()
+		os.Remove(path)
+		return nil, f.err
+	}
+	f.startSync()
+	return f, nil
+}
+
+// Resume reabre um arquivo de estado para continuar o scan. O estado precisa
+// ter sido salvo pelo mesmo comando com a mesma configuração; caso
+// contrário o erro é ErrIncompatible. Uma última linha incompleta (escrita
+// interrompida) é descartada.
+func Resume(path, command, configHash string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir estado: %w", err)
+	}
+
+	f := &File{
+		path: path,
+		file: file,
+		jobs: map[string]scanner.ScanJobResult{},
+	}
+	valid, err := f.load(command, configHash)
+	if err == nil {
+		// Novos registros continuam após a última linha válida
+		if err = file.Truncate(valid); err == nil {
+			_, err = file.Seek(valid, 0)
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	f.buf = bufio.NewWriter(file)
+	f.startSync()
+	return f, nil
+}
+
+// load lê os registros do arquivo e retorna o tamanho da parte válida
+func (f *File) load(command, configHash string) (int64, error) {
+	reader := bufio.NewReader(f.file)
+	var valid int64
+	for line := 0; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil {
+			if line == 0 {
+				return 0, fmt.Errorf("%w: %s está vazio", ErrIncompatible, f.path)
+			}
+			// Sem '\n' final a linha foi cortada no meio da escrita
+			return valid, nil
+		}
+
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return 0, fmt.Errorf("estado corrompido em %s, linha %d: %w", f.path, line+1, err)
+		}
+
+		if line == 0 {
+			if rec.Type != recordHeader {
+				return 0, fmt.Errorf("%w: %s não é um arquivo de estado", ErrIncompatible, f.path)
+			}
+			if err := checkHeader(rec, command, configHash); err != nil {
+				return 0, err
+			}
+			f.started = rec.Started
+		}
+
+		switch rec.Type {
+		case recordTargets:
+			f.targets = rec.Targets
+		case recordJob:
+			if rec.Result != nil {
+				f.jobs[rec.Key] = *rec.Result
+			}
+		}
+		valid += int64(len(data))
+	}
+}
+
+// checkHeader recusa estados de outra versão, comando ou configuração
+func checkHeader(rec record, command, configHash string) error {
+	switch {
+	case rec.Version != version:
+		return fmt.Errorf("%w: versão %d do formato (esperado %d)", ErrIncompatible, rec.Version, version)
+	case rec.Command != command:
+		return fmt.Errorf("%w: salvo pelo comando %s, não por %s", ErrIncompatible, rec.Command, command)
+	case rec.Config != configHash:
+		return fmt.Errorf("%w: salvo com outra configuração (alvo, descoberta, verificações ou payloads mudaram)", ErrIncompatible)
+	}
+	return nil
+}
+
+// append acrescenta um registro ao buffer; ele chega ao disco na próxima
+// sincronização. A primeira falha fica em f.err e interrompe as gravações
+// seguintes.
+func (f *File) append(rec record) {
+	if f.err != nil || f.closed {
+		return
+	}
+	data, err := json.Marshal(rec)
+	if err == nil {
+		_, err = f.buf.Write(append(data, '\n'))
+	}
+	f.fail(err)
+}
+
+// flush grava o buffer no arquivo e informa se havia algo a gravar
+func (f *File) flush() bool {
+	if f.err != nil || f.buf.Buffered() == 0 {
+		return false
+	}
+	f.fail(f.buf.Flush())
+	return f.err == nil
+}
+
+// sync grava o buffer e sincroniza o arquivo com o disco, para os registros
+// sobreviverem a uma queda do sistema
+func (f *File) sync() {
+	if f.flush() {
+		f.fail(f.file.Sync())
+	}
+}
+
+// startSync inicia a sincronização periódica dos registros
+func (f *File) startSync() {
+	f.stop = make(chan struct{})
+	f.done = make(chan struct{})
+	go func() {
+		defer close(f.done)
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+			}
+			// Só a cópia para o arquivo segura o mutex: Record não espera
+			// o disco
+			f.mu.Lock()
+			wrote := f.flush()
+			f.mu.Unlock()
+			if !wrote {
+				continue
+			}
+			if err := f.file.Sync(); err != nil {
+				f.mu.Lock()
+				f.fail(err)
+				f.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// fail guarda a primeira falha de escrita
+func (f *File) fail(err error) {
+	if err != nil && f.err == nil {
+		f.err = fmt.Errorf("erro ao gravar estado em %s: %w", f.path, err)
+	}
+}
+
+// Path retorna o caminho do arquivo de estado
+func (f *File) Path() string {
+	return f.path
+}
+
+// Started retorna o início do scan original
+func (f *File) Started() time.Time {
+	return f.started
+}
+
+// Targets retorna os alvos salvos, ou nil se a descoberta não terminou
+// antes da interrupção
+func (f *File) Targets() *Targets {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.targets
+}
+
+// SaveTargets salva os alvos descobertos
+func (f *File) SaveTargets(targets Targets) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets = &targets
+	f.append(record{Type: recordTargets, Targets: &targets})
+	f.sync()
+	return f.err
+}
+
+// Lookup retorna o resultado salvo do job
+func (f *File) Lookup(key string) (scanner.ScanJobResult, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result, ok := f.jobs[key]
+	return result, ok
+}
+
+// Record salva o resultado de um job concluído. Falhas de escrita não
+// interrompem o scan; são retornadas por Close.
+func (f *File) Record(key string, result scanner.ScanJobResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jobs[key] = result
+	f.append(record{Type: recordJob, Key: key, Result: &result})
+}
+
+// Completed retorna o número de jobs concluídos salvos
+func (f *File) Completed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.jobs)
+}
+
+// Close grava os registros pendentes, fecha o arquivo e retorna a primeira
+// falha de escrita, se houve
+func (f *File) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		defer f.mu.Unlock()
+		return f.err
+	}
+	f.closed = true
+	f.mu.Unlock()
+
+	// A sincronização periódica usa o arquivo fora do mutex
+	close(f.stop)
+	<-f.done
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sync()
+	if err := f.file.Close(); err != nil && f.err == nil {
+		f.err = err
+	}
+	return f.err
+}
+
+// Remove fecha e apaga o arquivo de estado, para scans concluídos
+func (f *File) Remove() error {
+	f.Close()
+	return os.Remove(f.path)
+}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"furador-de-coco/finding"
+	"furador-de-coco/scanner"
+)
+
+func TestResume(t *testing.T) {
+	dir := t.TempDir()
+
+	st, err := Create(dir, "scan", "abc", "http://alvo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forms := []scanner.Form{{Action: "/busca", Method: "GET", Inputs: []scanner.Input{{Name: "q", Type: "text"}}}}
+	if err := st.SaveTargets(Targets{Forms: forms, Pages: []string{"http://alvo/"}}); err != nil {
+		t.Fatal(err)
+	}
+	st.Record("form:0", scanner.ScanJobResult{
+		XSSVuln:    true,
+		XSSResults: []scanner.XSSResult{{Vulnerable: true, Field: "q"}},
+		Findings:   []finding.Finding{{CheckID: "xss", Title: "XSS"}},
+	})
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simula uma escrita cortada pela interrupção
+	file, err := os.OpenFile(st.Path(), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"type":"job","key":"form:1","res`)
+	file.Close()
+
+	resumed, err := Resume(st.Path(), "scan", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if targets := resumed.Targets(); targets == nil || len(targets.Forms) != 1 || targets.Forms[0].Inputs[0].Name != "q" {
+		t.Errorf("alvos = %+v", targets)
+	}
+	if !resumed.Started().Equal(st.Started()) {
+		t.Errorf("início = %v, esperado %v", resumed.Started(), st.Started())
+	}
+	saved, ok := resumed.Lookup("form:0")
+	if !ok || !saved.XSSVuln || len(saved.Findings) != 1 {
+		t.Errorf("job salvo = %+v, %v", saved, ok)
+	}
+	if _, ok := resumed.Lookup("form:1"); ok {
+		t.Error("linha cortada carregada como job concluído")
+	}
+
+	// Novos registros continuam após a linha cortada
+	resumed.Record("form:1", scanner.ScanJobResult{})
+	if err := resumed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Resume(st.Path(), "scan", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if got := again.Completed(); got != 2 {
+		t.Errorf("jobs concluídos = %d, esperado 2", got)
+	}
+}
+
+func TestPeriodicSync(t *testing.T) {
+	st, err := Create(t.TempDir(), "scan", "abc", "http://alvo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	// O job chega ao disco sem Close, na sincronização periódica
+	st.Record("form:0", scanner.ScanJobResult{})
+	deadline := time.Now().Add(3 * syncInterval)
+	for {
+		data, err := os.ReadFile(st.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), `"key":"form:0"`) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job não gravado após %v:\n%s", 3*syncInterval, data)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestResumeIncompatible(t *testing.T) {
+	dir := t.TempDir()
+	st, err := Create(dir, "scan", "abc", "http://alvo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+
+	tests := []struct {
+		name    string
+		command string
+		config  string
+	}{
+		{"Outra configuração", "scan", "def"},
+		{"Outro comando", "advanced", "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Resume(st.Path(), tt.command, tt.config); !errors.Is(err, ErrIncompatible) {
+				t.Errorf("erro = %v, esperado ErrIncompatible", err)
+			}
+		})
+	}
+}
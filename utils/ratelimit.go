@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits são os limites aplicados a todas as requisições de um client
+type Limits struct {
+	// Requisições por segundo, somando todos os workers (0 = sem limite)
+	RPS float64
+	// Requisições que podem sair de uma vez após um período ocioso
+	Burst int
+	// Requisições em andamento por host (0 = sem limite)
+	MaxPerHost int
+	// Novas tentativas de requisições idempotentes após 429/503 ou conexão
+	// derrubada
+	MaxRetries int
+}
+
+// RateLimiter é um token bucket: os tokens se acumulam a RPS por segundo até
+// Burst, e cada requisição consome um
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter cria um token bucket cheio
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait reserva um token e espera até ele ficar disponível. Com o contexto
+// cancelado durante a espera o token é devolvido e o erro do contexto
+// retornado.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// limitedTransport aplica o rate limiter global, o limite por host e o
+// backoff antes de repassar a requisição ao transport original
+type limitedTransport struct {
+	base       http.RoundTripper
+	limiter    *RateLimiter
+	maxPerHost int
+	maxRetries int
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// hostState guarda as vagas de um host e até quando as requisições a ele
+// estão suspensas pelo backoff
+type hostState struct {
+	slots       chan struct{}
+	pausedUntil time.Time
+}
+
+// LimitClient envolve o transport do client com os limites informados. O
+// client é alterado e retornado; todas as goroutines que o usam passam a
+// dividir o mesmo orçamento de requisições e o mesmo backoff.
+func LimitClient(client *http.Client, limits Limits) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &limitedTransport{
+		base:       base,
+		maxPerHost: limits.MaxPerHost,
+		maxRetries: limits.MaxRetries,
+		hosts:      map[string]*hostState{},
+	}
+	if limits.RPS > 0 {
+		t.limiter = NewRateLimiter(limits.RPS, limits.Burst)
+	}
+	client.Transport = t
+	return client
+}
+
+// RoundTrip envia a requisição respeitando os limites. Respostas 429/503 e
+// conexões derrubadas suspendem todas as requisições ao host pelo tempo do
+// Retry-After ou do backoff exponencial; requisições idempotentes são
+// repetidas até MaxRetries vezes. Se o alvo continuar limitando, o erro é
+// ErrThrottled.
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := req.URL.Host
+
+	for attempt := 0; ; attempt++ {
+		if err := t.waitPause(ctx, host); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.send(req)
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !connectionDropped(err) {
+				return nil, err
+			}
+			delay = backoff(attempt)
+		case overloaded(resp.StatusCode):
+			delay = retryAfter(resp.Header.Get("Retry-After"), attempt)
+			err = fmt.Errorf("%w: status %d", ErrThrottled, resp.StatusCode)
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		t.pause(host, delay)
+		if attempt >= t.maxRetries || !replayable(req) {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send envia uma tentativa, com vaga no host e token do rate limiter
+func (t *limitedTransport) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// A vaga no host vem antes do token, para não gastar tokens com
+	// requisições que ainda vão ficar esperando
+	release, err := t.acquire(ctx, req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// A requisição continua em andamento até o corpo ser fechado
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// host retorna o estado do host, criando-o na primeira requisição
+func (t *limitedTransport) host(host string) *hostState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.hosts[host]
+	if !ok {
+		state = &hostState{}
+		if t.maxPerHost > 0 {
+			state.slots = make(chan struct{}, t.maxPerHost)
+		}
+		t.hosts[host] = state
+	}
+	return state
+}
+
+// acquire ocupa uma vaga do host e retorna a função que a libera
+func (t *limitedTransport) acquire(ctx context.Context, host string) (func(), error) {
+	slots := t.host(host).slots
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { <-slots }) }, nil
+}
+
+// pause suspende as requisições ao host por d. Uma pausa mais longa já em
+// vigor é mantida.
+func (t *limitedTransport) pause(host string, d time.Duration) {
+	state := t.host(host)
+	until := time.Now().Add(d)
+	t.mu.Lock()
+	if until.After(state.pausedUntil) {
+		state.pausedUntil = until
+	}
+	t.mu.Unlock()
+}
+
+// waitPause espera o fim da pausa do host, se houver
+func (t *limitedTransport) waitPause(ctx context.Context, host string) error {
+	state := t.host(host)
+	t.mu.Lock()
+	wait := time.Until(state.pausedUntil)
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseBody libera a vaga do host quando o corpo da resposta é fechado
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
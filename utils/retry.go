@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ErrThrottled indica que o alvo respondeu 429 ou 503 e a requisição não
+// pôde ser repetida ou esgotou as novas tentativas
+var ErrThrottled = errors.New("alvo limitando requisições")
+
+const (
+	// Espera da primeira nova tentativa; dobra a cada tentativa
+	backoffBase = 500 * time.Millisecond
+	// Teto do backoff exponencial
+	backoffMax = 30 * time.Second
+	// Teto do Retry-After, para um valor absurdo não travar o scan
+	retryAfterMax = 2 * time.Minute
+)
+
+// overloaded indica status de sobrecarga ou limite de taxa do alvo
+func overloaded(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// connectionDropped indica conexão recusada ou derrubada pelo alvo, comum
+// quando um WAF ou o próprio servidor começa a rejeitar o scanner
+func connectionDropped(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff é a espera exponencial da tentativa, com até 25% de variação
+// aleatória para os workers não voltarem todos juntos
+func backoff(attempt int) time.Duration {
+	d := backoffBase << min(attempt, 10)
+	if d > backoffMax {
+		d = backoffMax
+	}
+	return d + rand.N(d/4+1)
+}
+
+// retryAfter interpreta o header Retry-After (segundos ou data HTTP). Sem o
+// header, ou com valor inválido, usa o backoff exponencial.
+func retryAfter(value string, attempt int) time.Duration {
+	var d time.Duration
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		d = time.Duration(secs) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		d = max(time.Until(date), 0)
+	} else {
+		return backoff(attempt)
+	}
+	return min(d, retryAfterMax)
+}
+
+// replayable indica se a requisição pode ser repetida: método idempotente e
+// corpo que pode ser lido de novo
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind prepara uma cópia da requisição para a nova tentativa, com o corpo
+// desde o início
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, nil
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimitClientRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		failures  int32 // Respostas 429 antes do sucesso
+		status    int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"GET repetido até o sucesso", http.MethodGet, 2, http.StatusTooManyRequests, 3, false},
+		{"GET esgota as tentativas", http.MethodGet, 10, http.StatusServiceUnavailable, 3, true},
+		{"POST não é repetido", http.MethodPost, 1, http.StatusTooManyRequests, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			client := LimitClient(server.Client(), Limits{MaxRetries: 2})
+			req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("a=1"))
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("erro = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrThrottled) {
+				t.Errorf("erro = %v, esperado ErrThrottled", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("requisições = %d, esperado %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"Segundos", "2", 2 * time.Second, 2 * time.Second},
+		{"Data HTTP", time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), 8 * time.Second, 10 * time.Second},
+		{"Data passada", "Mon, 02 Jan 2006 15:04:05 GMT", 0, 0},
+		{"Acima do teto", "86400", retryAfterMax, retryAfterMax},
+		{"Ausente usa backoff", "", backoffBase, backoffBase * 5 / 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.value, 0); got < tt.min || got > tt.max {
+				t.Errorf("retryAfter(%q) = %v, esperado entre %v e %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}